@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/config"
+	"github.com/anujgupta/level-up-backend/internal/content"
+	appdb "github.com/anujgupta/level-up-backend/internal/db"
+)
+
+const contentUsage = "usage: levelup content import|export <dir>"
+
+// runContent implements `levelup content import <dir>` and
+// `levelup content export <dir>`.
+func runContent(args []string) error {
+	if len(args) != 2 || (args[0] != "import" && args[0] != "export") {
+		return errors.New(contentUsage)
+	}
+	command, dir := args[0], args[1]
+
+	// Validate the source tree before touching the database.
+	var modules []content.Module
+	if command == "import" {
+		var err error
+		if modules, err = content.Read(dir); err != nil {
+			return fmt.Errorf("read %s: %w", dir, err)
+		}
+	}
+
+	databaseURL := config.LoadDatabaseURL()
+	pool, err := appdb.Connect(databaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	if err := appdb.RunMigrations(databaseURL); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	if command == "export" {
+		modules, err := content.Export(ctx, dbgen.New(pool))
+		if err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+		if err := content.Write(dir, modules); err != nil {
+			return fmt.Errorf("write %s: %w", dir, err)
+		}
+		fmt.Printf("exported %d modules to %s\n", len(modules), dir)
+		return nil
+	}
+
+	summary, err := content.Import(ctx, pool, modules)
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+	fmt.Printf("imported %d modules, %d lessons, %d skills (%d new lesson revisions)\n",
+		summary.Modules, summary.Lessons, summary.Skills, summary.Revisions)
+	for _, stale := range summary.Stale {
+		fmt.Printf("not in %s, left untouched: %s\n", dir, stale)
+	}
+	return nil
+}
@@ -25,6 +25,14 @@ func main() {
 		Level: slog.LevelInfo,
 	}))
 
+	if len(os.Args) > 1 && os.Args[1] == "content" {
+		if err := runContent(os.Args[2:]); err != nil {
+			logger.Error("content command failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(logger); err != nil {
 		logger.Error("server error", "err", err)
 		os.Exit(1)
@@ -56,7 +64,10 @@ func run(logger *slog.Logger) error {
 	queries := dbgen.New(pool)
 
 	// 5. Build auth service
-	authSvc := auth.NewService(cfg)
+	authSvc, err := auth.NewService(cfg)
+	if err != nil {
+		return err
+	}
 
 	// 6. Build and start mailer
 	mailerSvc := mailer.New(cfg, logger)
@@ -64,7 +75,7 @@ func run(logger *slog.Logger) error {
 	logger.Info("mailer started", "workers", 3)
 
 	// 7. Build server (wires all handlers + middleware + router)
-	srv := server.New(cfg, queries, authSvc, mailerSvc, logger)
+	srv := server.New(cfg, pool, queries, authSvc, mailerSvc, logger)
 
 	// 8. Graceful shutdown on SIGINT / SIGTERM
 	quit := make(chan os.Signal, 1)
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- Accounts created before verification existed are trusted as they are;
+-- otherwise current subscribers would be blocked from checkout.
+UPDATE users SET email_verified_at = created_at;
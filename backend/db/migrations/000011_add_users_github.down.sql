@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_github_id;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS github_login,
+    DROP COLUMN IF EXISTS github_id;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN github_id    BIGINT UNIQUE,
+    ADD COLUMN github_login TEXT;
+
+CREATE INDEX idx_users_github_id ON users (github_id);
@@ -0,0 +1,14 @@
+CREATE TABLE impersonation_sessions (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id        UUID REFERENCES users(id) ON DELETE SET NULL,
+    target_user_id  UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason          TEXT NOT NULL,
+    ip_address      TEXT NOT NULL DEFAULT '',
+    user_agent      TEXT NOT NULL DEFAULT '',
+    started_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ NOT NULL,
+    ended_at        TIMESTAMPTZ
+);
+
+CREATE INDEX idx_impersonation_sessions_admin_id ON impersonation_sessions (admin_id);
+CREATE INDEX idx_impersonation_sessions_target_user_id ON impersonation_sessions (target_user_id);
@@ -0,0 +1,8 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+UPDATE users SET role = 'user' WHERE role NOT IN ('user', 'admin');
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin'));
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,44 @@
+CREATE TABLE roles (
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE permissions (
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role       TEXT NOT NULL REFERENCES roles(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission TEXT NOT NULL REFERENCES permissions(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('user',           'Learner with no staff access'),
+    ('admin',          'Full staff access'),
+    ('reviewer',       'Can see and review submissions'),
+    ('content_editor', 'Can edit the curriculum');
+
+INSERT INTO permissions (name, description) VALUES
+    ('submissions:read',   'View any learner''s submissions'),
+    ('submissions:review', 'Review submissions and leave feedback'),
+    ('content:edit',       'Create, edit and reorder modules, lessons and skills'),
+    ('users:manage',       'Unlock accounts and change user roles'),
+    ('users:impersonate',  'Impersonate learners and read the impersonation audit trail');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin',          'submissions:read'),
+    ('admin',          'submissions:review'),
+    ('admin',          'content:edit'),
+    ('admin',          'users:manage'),
+    ('admin',          'users:impersonate'),
+    ('reviewer',       'submissions:read'),
+    ('reviewer',       'submissions:review'),
+    ('content_editor', 'content:edit');
+
+-- Roles now live in the roles table instead of a CHECK constraint.
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users
+    ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
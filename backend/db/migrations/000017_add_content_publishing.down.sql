@@ -0,0 +1,20 @@
+-- Put the published content back into lessons so learners keep seeing it.
+UPDATE lessons l
+SET
+    title   = lr.title,
+    content = lr.content
+FROM lesson_revisions lr
+WHERE lr.id = l.published_revision_id;
+
+ALTER TABLE lessons DROP COLUMN IF EXISTS published_revision_id;
+DROP TABLE IF EXISTS lesson_revisions;
+
+ALTER TABLE lessons
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
+
+ALTER TABLE modules
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS content_status;
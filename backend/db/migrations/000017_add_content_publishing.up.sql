@@ -0,0 +1,41 @@
+CREATE TYPE content_status AS ENUM (
+    'draft',
+    'scheduled',
+    'published',
+    'archived'
+);
+
+ALTER TABLE modules
+    ADD COLUMN status     content_status NOT NULL DEFAULT 'draft',
+    ADD COLUMN publish_at TIMESTAMPTZ;
+
+ALTER TABLE lessons
+    ADD COLUMN status     content_status NOT NULL DEFAULT 'draft',
+    ADD COLUMN publish_at TIMESTAMPTZ;
+
+CREATE TABLE lesson_revisions (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    lesson_id       UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    revision_number INTEGER NOT NULL,
+    title           TEXT NOT NULL,
+    content         TEXT NOT NULL,
+    author_id       UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (lesson_id, revision_number)
+);
+
+ALTER TABLE lessons
+    ADD COLUMN published_revision_id UUID REFERENCES lesson_revisions(id) ON DELETE SET NULL;
+
+-- Everything that existed before this migration was live: keep it published,
+-- with its current content as revision 1.
+UPDATE modules SET status = 'published';
+UPDATE lessons SET status = 'published';
+
+INSERT INTO lesson_revisions (lesson_id, revision_number, title, content)
+SELECT id, 1, title, content FROM lessons;
+
+UPDATE lessons l
+SET published_revision_id = lr.id
+FROM lesson_revisions lr
+WHERE lr.lesson_id = l.id AND lr.revision_number = 1;
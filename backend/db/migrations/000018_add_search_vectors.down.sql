@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_lesson_revisions_search;
+DROP INDEX IF EXISTS idx_skills_search;
+DROP INDEX IF EXISTS idx_modules_search;
+
+DROP FUNCTION IF EXISTS lesson_search_vector(TEXT, TEXT);
+DROP FUNCTION IF EXISTS skill_search_vector(TEXT);
+DROP FUNCTION IF EXISTS module_search_vector(TEXT, TEXT);
@@ -0,0 +1,38 @@
+-- Full-text search. Weights: A = titles, B = Markdown headings, C = body text.
+--
+-- The vectors only exist inside GIN expression indexes rather than as
+-- columns, so the many SELECT * reads of these tables don't drag them along.
+-- Queries must call the same functions for the indexes to be used.
+
+CREATE FUNCTION module_search_vector(title TEXT, description TEXT)
+RETURNS TSVECTOR AS $$
+    SELECT
+        setweight(to_tsvector('english'::regconfig, title), 'A') ||
+        setweight(to_tsvector('english'::regconfig, description), 'C')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION skill_search_vector(skill_name TEXT)
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('english'::regconfig, skill_name), 'A')
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Lessons are searched through their published revision rather than the
+-- working copy, so search never surfaces unpublished edits.
+--
+-- Headings are the lines starting with '#' once fenced code blocks are
+-- removed, since '#' also starts comments in shell, Python and YAML snippets.
+-- The 'w' flag lets ^ match at line starts while . still spans lines.
+CREATE FUNCTION lesson_search_vector(title TEXT, content TEXT)
+RETURNS TSVECTOR AS $$
+    SELECT
+        setweight(to_tsvector('english'::regconfig, title), 'A') ||
+        setweight(to_tsvector('english'::regconfig, regexp_replace(
+            regexp_replace(content, '^(```|~~~).*?^(```|~~~)', '', 'gw'),
+            '^[^#].*$', '', 'gn'
+        )), 'B') ||
+        setweight(to_tsvector('english'::regconfig, content), 'C')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX idx_modules_search ON modules USING GIN (module_search_vector(title, description));
+CREATE INDEX idx_skills_search ON skills USING GIN (skill_search_vector(skill_name));
+CREATE INDEX idx_lesson_revisions_search ON lesson_revisions USING GIN (lesson_search_vector(title, content));
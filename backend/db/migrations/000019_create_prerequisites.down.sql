@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lesson_prerequisites;
+DROP TABLE IF EXISTS module_prerequisites;
@@ -0,0 +1,19 @@
+CREATE TABLE module_prerequisites (
+    module_id          UUID NOT NULL REFERENCES modules(id) ON DELETE CASCADE,
+    required_module_id UUID NOT NULL REFERENCES modules(id) ON DELETE CASCADE,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (module_id, required_module_id),
+    CHECK (module_id <> required_module_id)
+);
+
+CREATE INDEX idx_module_prerequisites_required ON module_prerequisites (required_module_id);
+
+CREATE TABLE lesson_prerequisites (
+    lesson_id          UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    required_lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (lesson_id, required_lesson_id),
+    CHECK (lesson_id <> required_lesson_id)
+);
+
+CREATE INDEX idx_lesson_prerequisites_required ON lesson_prerequisites (required_lesson_id);
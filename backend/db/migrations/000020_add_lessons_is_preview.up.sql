@@ -0,0 +1,3 @@
+-- Preview lessons can be read without an active subscription.
+ALTER TABLE lessons
+    ADD COLUMN is_preview BOOLEAN NOT NULL DEFAULT FALSE;
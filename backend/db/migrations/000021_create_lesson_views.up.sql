@@ -0,0 +1,13 @@
+-- The latest reading position per user and lesson, refreshed whenever the
+-- lesson is served or the client sends a heartbeat.
+CREATE TABLE lesson_views (
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lesson_id       UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    started_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_viewed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    scroll_position DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (scroll_position BETWEEN 0 AND 1),
+    section         TEXT,
+    PRIMARY KEY (user_id, lesson_id)
+);
+
+CREATE INDEX idx_lesson_views_user_last_viewed ON lesson_views (user_id, last_viewed_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS skill_progress_archive;
+DROP TABLE IF EXISTS lesson_progress_archive;
@@ -0,0 +1,21 @@
+-- Completions cleared by a module reset are moved here so the learner's
+-- history survives starting over.
+CREATE TABLE lesson_progress_archive (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lesson_id    UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    completed_at TIMESTAMPTZ NOT NULL,
+    archived_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_lesson_progress_archive_user_id ON lesson_progress_archive (user_id);
+
+CREATE TABLE skill_progress_archive (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    skill_id     UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    completed_at TIMESTAMPTZ NOT NULL,
+    archived_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_skill_progress_archive_user_id ON skill_progress_archive (user_id);
@@ -0,0 +1,2 @@
+-- IANA time zone used to bucket activity into the user's calendar days.
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
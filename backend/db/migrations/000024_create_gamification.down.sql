@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_badges;
+DROP TABLE IF EXISTS xp_events;
+DROP TYPE IF EXISTS xp_source;
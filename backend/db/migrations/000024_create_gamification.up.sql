@@ -0,0 +1,22 @@
+CREATE TYPE xp_source AS ENUM ('lesson', 'skill', 'submission');
+
+-- XP ledger. Each lesson, skill or submission awards XP at most once per user.
+CREATE TABLE xp_events (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source     xp_source NOT NULL,
+    source_id  UUID NOT NULL,
+    xp         INTEGER NOT NULL CHECK (xp > 0),
+    awarded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, source, source_id)
+);
+
+CREATE INDEX idx_xp_events_user_id ON xp_events (user_id);
+
+-- Badges are defined in code; this records who earned which and when.
+CREATE TABLE user_badges (
+    user_id   UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    badge     TEXT NOT NULL,
+    earned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, badge)
+);
@@ -0,0 +1,13 @@
+-- One certificate per user and module. The id doubles as the public
+-- verification ID. Name and title are copied at issue time so a certificate
+-- keeps saying what it said when it was issued, and it stays verifiable
+-- after its module is deleted.
+CREATE TABLE certificates (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    module_id      UUID REFERENCES modules(id) ON DELETE SET NULL,
+    recipient_name TEXT NOT NULL,
+    module_title   TEXT NOT NULL,
+    issued_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, module_id)
+);
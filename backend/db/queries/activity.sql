@@ -0,0 +1,32 @@
+-- name: ListActivityByDay :many
+-- Per-day activity counts over the user's whole history, with days taken in
+-- the given time zone. Completions cleared by a module reset still count.
+WITH events AS (
+    SELECT 'lesson'::text AS kind, ulp.completed_at AS at
+    FROM user_lesson_progress ulp
+    WHERE ulp.user_id = sqlc.arg(user_id)
+    UNION ALL
+    SELECT 'lesson', lpa.completed_at
+    FROM lesson_progress_archive lpa
+    WHERE lpa.user_id = sqlc.arg(user_id)
+    UNION ALL
+    SELECT 'skill', usp.completed_at
+    FROM user_skill_progress usp
+    WHERE usp.user_id = sqlc.arg(user_id)
+    UNION ALL
+    SELECT 'skill', spa.completed_at
+    FROM skill_progress_archive spa
+    WHERE spa.user_id = sqlc.arg(user_id)
+    UNION ALL
+    SELECT 'submission', s.submitted_at
+    FROM submissions s
+    WHERE s.user_id = sqlc.arg(user_id)
+)
+SELECT
+    (e.at AT TIME ZONE sqlc.arg(timezone)::text)::date AS day,
+    COUNT(*) FILTER (WHERE e.kind = 'lesson')::int     AS lessons,
+    COUNT(*) FILTER (WHERE e.kind = 'skill')::int      AS skills,
+    COUNT(*) FILTER (WHERE e.kind = 'submission')::int AS submissions
+FROM events e
+GROUP BY day
+ORDER BY day ASC;
@@ -7,3 +7,18 @@ LIMIT 1;
 SELECT * FROM assignments
 WHERE id = $1
 LIMIT 1;
+
+-- name: UpsertAssignment :one
+INSERT INTO assignments (module_id, title, description, rubric, estimated_hours)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (module_id) DO UPDATE
+SET
+    title           = EXCLUDED.title,
+    description     = EXCLUDED.description,
+    rubric          = EXCLUDED.rubric,
+    estimated_hours = EXCLUDED.estimated_hours
+RETURNING *;
+
+-- name: DeleteAssignmentByModuleID :execrows
+DELETE FROM assignments
+WHERE module_id = $1;
@@ -0,0 +1,17 @@
+-- name: IssueCertificate :one
+-- Issuing again returns the existing certificate unchanged.
+INSERT INTO certificates (user_id, module_id, recipient_name, module_title)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, module_id) DO UPDATE
+SET user_id = certificates.user_id
+RETURNING *;
+
+-- name: GetCertificate :one
+SELECT * FROM certificates
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListUserCertificates :many
+SELECT * FROM certificates
+WHERE user_id = $1
+ORDER BY issued_at ASC;
@@ -0,0 +1,57 @@
+-- name: AwardXP :execrows
+INSERT INTO xp_events (user_id, source, source_id, xp)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, source, source_id) DO NOTHING;
+
+-- name: RevokeXP :execrows
+DELETE FROM xp_events
+WHERE user_id = $1 AND source = $2 AND source_id = $3;
+
+-- name: GetUserXP :one
+SELECT COALESCE(SUM(xp), 0)::int AS xp
+FROM xp_events
+WHERE user_id = $1;
+
+-- name: GetBadgeStats :one
+-- The counters badge rules are evaluated against. A module counts as finished
+-- once every one of its published lessons is completed.
+SELECT
+    (SELECT COUNT(*) FROM user_lesson_progress ulp WHERE ulp.user_id = $1)::int AS lessons_completed,
+    (SELECT COUNT(*) FROM user_skill_progress usp WHERE usp.user_id = $1)::int AS skills_completed,
+    (SELECT COUNT(*) FROM submissions s WHERE s.user_id = $1 AND s.status = 'approved')::int AS approved_submissions,
+    (
+        SELECT COUNT(*)
+        FROM modules m
+        WHERE EXISTS (
+            SELECT 1 FROM lessons l
+            WHERE l.module_id = m.id
+              AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+        )
+          AND NOT EXISTS (
+            SELECT 1 FROM lessons l
+            WHERE l.module_id = m.id
+              AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+              AND NOT EXISTS (
+                  SELECT 1 FROM user_lesson_progress ulp
+                  WHERE ulp.user_id = $1 AND ulp.lesson_id = l.id
+              )
+        )
+    )::int AS modules_completed,
+    (SELECT COALESCE(SUM(xp), 0) FROM xp_events x WHERE x.user_id = $1)::int AS xp;
+
+-- name: ListUserBadges :many
+SELECT badge, earned_at
+FROM user_badges
+WHERE user_id = $1
+ORDER BY earned_at ASC;
+
+-- name: AddUserBadge :execrows
+INSERT INTO user_badges (user_id, badge)
+VALUES ($1, $2)
+ON CONFLICT (user_id, badge) DO NOTHING;
+
+-- name: ExportUserXPEvents :many
+SELECT source, source_id, xp, awarded_at
+FROM xp_events
+WHERE user_id = $1
+ORDER BY awarded_at ASC;
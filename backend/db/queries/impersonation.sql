@@ -0,0 +1,20 @@
+-- name: CreateImpersonationSession :one
+INSERT INTO impersonation_sessions (admin_id, target_user_id, reason, ip_address, user_agent, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: IsImpersonationSessionActive :one
+SELECT EXISTS (
+    SELECT 1 FROM impersonation_sessions
+    WHERE id = $1 AND ended_at IS NULL AND expires_at > NOW()
+);
+
+-- name: EndImpersonationSession :execrows
+UPDATE impersonation_sessions
+SET ended_at = NOW()
+WHERE id = $1 AND ended_at IS NULL;
+
+-- name: ListImpersonationSessions :many
+SELECT * FROM impersonation_sessions
+ORDER BY started_at DESC
+LIMIT $1 OFFSET $2;
@@ -0,0 +1,39 @@
+-- name: CreateLessonRevision :one
+INSERT INTO lesson_revisions (lesson_id, revision_number, title, content, author_id)
+VALUES (
+    $1,
+    (SELECT COALESCE(MAX(revision_number), 0) + 1 FROM lesson_revisions WHERE lesson_id = $1),
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: ListLessonRevisions :many
+SELECT
+    lr.id,
+    lr.revision_number,
+    lr.title,
+    lr.author_id,
+    u.name AS author_name,
+    lr.created_at
+FROM lesson_revisions lr
+LEFT JOIN users u ON u.id = lr.author_id
+WHERE lr.lesson_id = $1
+ORDER BY lr.revision_number DESC;
+
+-- name: GetLessonRevision :one
+SELECT * FROM lesson_revisions
+WHERE lesson_id = $1 AND revision_number = $2
+LIMIT 1;
+
+-- name: GetLessonRevisionByID :one
+SELECT * FROM lesson_revisions
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetLatestLessonRevision :one
+SELECT * FROM lesson_revisions
+WHERE lesson_id = $1
+ORDER BY revision_number DESC
+LIMIT 1;
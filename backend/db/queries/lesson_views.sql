@@ -0,0 +1,79 @@
+-- name: RecordLessonView :one
+-- Position fields left NULL keep their stored value, so serving a lesson only
+-- bumps last_viewed_at.
+INSERT INTO lesson_views (user_id, lesson_id, scroll_position, section)
+VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(lesson_id),
+    COALESCE(sqlc.narg(scroll_position)::double precision, 0),
+    sqlc.narg(section)
+)
+ON CONFLICT (user_id, lesson_id) DO UPDATE
+SET
+    last_viewed_at  = NOW(),
+    scroll_position = COALESCE(sqlc.narg(scroll_position)::double precision, lesson_views.scroll_position),
+    section         = COALESCE(sqlc.narg(section), lesson_views.section)
+RETURNING *;
+
+-- name: GetResumeLesson :one
+-- The most recently viewed published lesson the user hasn't completed.
+SELECT
+    l.id     AS lesson_id,
+    l.slug   AS lesson_slug,
+    lr.title AS lesson_title,
+    m.slug   AS module_slug,
+    m.title  AS module_title,
+    lv.started_at,
+    lv.last_viewed_at,
+    lv.scroll_position,
+    lv.section
+FROM lesson_views lv
+JOIN lessons l ON l.id = lv.lesson_id
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+JOIN modules m ON m.id = l.module_id
+WHERE lv.user_id = $1
+  AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+  AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+  AND NOT EXISTS (
+      SELECT 1 FROM user_lesson_progress ulp
+      WHERE ulp.user_id = lv.user_id AND ulp.lesson_id = l.id
+  )
+ORDER BY lv.last_viewed_at DESC
+LIMIT 1;
+
+-- name: GetNextRecommendedLesson :one
+-- The first published lesson, in curriculum order, that the user hasn't
+-- completed, skipping the lesson they're already resuming.
+SELECT
+    l.id     AS lesson_id,
+    l.slug   AS lesson_slug,
+    lr.title AS lesson_title,
+    m.slug   AS module_slug,
+    m.title  AS module_title
+FROM lessons l
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+JOIN modules m ON m.id = l.module_id
+WHERE (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+  AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+  AND l.id IS DISTINCT FROM sqlc.narg(exclude_lesson_id)
+  AND NOT EXISTS (
+      SELECT 1 FROM user_lesson_progress ulp
+      WHERE ulp.user_id = sqlc.arg(user_id) AND ulp.lesson_id = l.id
+  )
+ORDER BY m.order_index ASC, l.order_index ASC
+LIMIT 1;
+
+-- name: ExportUserLessonViews :many
+SELECT
+    m.slug AS module_slug,
+    l.slug AS lesson_slug,
+    l.title AS lesson_title,
+    lv.started_at,
+    lv.last_viewed_at,
+    lv.scroll_position,
+    lv.section
+FROM lesson_views lv
+JOIN lessons l ON l.id = lv.lesson_id
+JOIN modules m ON m.id = l.module_id
+WHERE lv.user_id = $1
+ORDER BY lv.started_at ASC;
@@ -12,3 +12,116 @@ LIMIT 1;
 SELECT * FROM lessons
 WHERE id = $1
 LIMIT 1;
+
+-- name: CreateLesson :one
+INSERT INTO lessons (module_id, title, slug, content, estimated_minutes, is_preview, order_index)
+VALUES ($1, $2, $3, $4, $5, $6, (SELECT COALESCE(MAX(order_index), 0) + 1 FROM lessons WHERE module_id = $1))
+RETURNING *;
+
+-- name: UpdateLesson :one
+UPDATE lessons
+SET
+    title             = COALESCE(sqlc.narg(title), title),
+    slug              = COALESCE(sqlc.narg(slug), slug),
+    content           = COALESCE(sqlc.narg(content), content),
+    estimated_minutes = COALESCE(sqlc.narg(estimated_minutes), estimated_minutes),
+    is_preview        = COALESCE(sqlc.narg(is_preview), is_preview)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CountLessonProgress :one
+-- Learners who completed the lesson, now or before a module reset.
+SELECT
+    (SELECT COUNT(*) FROM user_lesson_progress ulp WHERE ulp.lesson_id = $1) +
+    (SELECT COUNT(*) FROM lesson_progress_archive lpa WHERE lpa.lesson_id = $1);
+
+-- name: DeleteLesson :execrows
+DELETE FROM lessons
+WHERE id = $1;
+
+-- name: SetLessonOrderIndex :exec
+UPDATE lessons
+SET order_index = $2
+WHERE id = $1;
+
+-- name: SetLessonStatus :one
+UPDATE lessons
+SET
+    status                = sqlc.arg(status),
+    publish_at            = sqlc.arg(publish_at),
+    published_revision_id = COALESCE(sqlc.narg(published_revision_id), published_revision_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetPublishedLessonsByModule :many
+SELECT
+    l.id,
+    l.module_id,
+    l.slug,
+    l.order_index,
+    l.estimated_minutes,
+    l.is_preview,
+    lr.title,
+    lr.content,
+    lr.id AS revision_id
+FROM lessons l
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+WHERE l.module_id = $1
+  AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+ORDER BY l.order_index ASC;
+
+-- name: GetPublishedLessonBySlug :one
+SELECT
+    l.id,
+    l.module_id,
+    l.slug,
+    l.order_index,
+    l.estimated_minutes,
+    l.is_preview,
+    lr.title,
+    lr.content,
+    lr.id AS revision_id
+FROM lessons l
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+WHERE l.module_id = $1
+  AND l.slug = $2
+  AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+LIMIT 1;
+
+-- name: IsLessonPublished :one
+SELECT EXISTS (
+    SELECT 1
+    FROM lessons l
+    JOIN modules m ON m.id = l.module_id
+    WHERE l.id = $1
+      AND l.published_revision_id IS NOT NULL
+      AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+      AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+);
+
+-- name: UpsertLesson :one
+INSERT INTO lessons (module_id, title, slug, content, estimated_minutes, is_preview, order_index)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (module_id, slug) DO UPDATE
+SET
+    title             = EXCLUDED.title,
+    content           = EXCLUDED.content,
+    estimated_minutes = EXCLUDED.estimated_minutes,
+    is_preview        = EXCLUDED.is_preview,
+    order_index       = EXCLUDED.order_index
+RETURNING *;
+
+-- name: ListPreviewLessons :many
+-- Published preview lessons of published modules, titled as published.
+SELECT
+    l.id,
+    l.module_id,
+    l.slug,
+    lr.title
+FROM lessons l
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+JOIN modules m ON m.id = l.module_id
+WHERE l.is_preview
+  AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+  AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+ORDER BY m.order_index ASC, l.order_index ASC;
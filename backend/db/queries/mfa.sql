@@ -0,0 +1,42 @@
+-- name: SetUserTOTPSecret :exec
+UPDATE users
+SET
+    totp_secret     = $2,
+    totp_enabled_at = NULL,
+    totp_last_step  = NULL
+WHERE id = $1;
+
+-- name: EnableUserTOTP :execrows
+UPDATE users
+SET totp_enabled_at = NOW()
+WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL;
+
+-- name: DisableUserTOTP :exec
+UPDATE users
+SET
+    totp_secret     = NULL,
+    totp_enabled_at = NULL,
+    totp_last_step  = NULL
+WHERE id = $1;
+
+-- name: RecordTOTPStep :execrows
+UPDATE users
+SET totp_last_step = $2
+WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2);
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO mfa_recovery_codes (user_id, code_hash)
+VALUES ($1, $2);
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
@@ -11,3 +11,71 @@ LIMIT 1;
 SELECT * FROM modules
 WHERE id = $1
 LIMIT 1;
+
+-- name: CreateModule :one
+INSERT INTO modules (title, slug, description, estimated_hours, order_index)
+VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(order_index), 0) + 1 FROM modules))
+RETURNING *;
+
+-- name: UpdateModule :one
+UPDATE modules
+SET
+    title           = COALESCE(sqlc.narg(title), title),
+    slug            = COALESCE(sqlc.narg(slug), slug),
+    description     = COALESCE(sqlc.narg(description), description),
+    estimated_hours = COALESCE(sqlc.narg(estimated_hours), estimated_hours)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteModule :execrows
+DELETE FROM modules
+WHERE id = $1;
+
+-- name: SetModuleOrderIndex :exec
+UPDATE modules
+SET order_index = $2
+WHERE id = $1;
+
+-- name: CountModuleSubmissions :one
+SELECT COUNT(*)
+FROM submissions s
+JOIN assignments a ON a.id = s.assignment_id
+WHERE a.module_id = $1;
+
+-- name: CountModuleProgress :one
+-- Lesson and skill completions in the module, now or before a module reset.
+SELECT
+    (SELECT COUNT(*) FROM user_lesson_progress ulp JOIN lessons l ON l.id = ulp.lesson_id WHERE l.module_id = $1) +
+    (SELECT COUNT(*) FROM lesson_progress_archive lpa JOIN lessons l ON l.id = lpa.lesson_id WHERE l.module_id = $1) +
+    (SELECT COUNT(*) FROM user_skill_progress usp JOIN skills s ON s.id = usp.skill_id WHERE s.module_id = $1) +
+    (SELECT COUNT(*) FROM skill_progress_archive spa JOIN skills s ON s.id = spa.skill_id WHERE s.module_id = $1);
+
+-- name: SetModuleStatus :one
+UPDATE modules
+SET
+    status     = $2,
+    publish_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: ListPublishedModules :many
+SELECT * FROM modules
+WHERE status = 'published' OR (status = 'scheduled' AND publish_at <= NOW())
+ORDER BY order_index ASC;
+
+-- name: GetPublishedModuleBySlug :one
+SELECT * FROM modules
+WHERE slug = $1
+  AND (status = 'published' OR (status = 'scheduled' AND publish_at <= NOW()))
+LIMIT 1;
+
+-- name: UpsertModule :one
+INSERT INTO modules (title, slug, description, estimated_hours, order_index)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (slug) DO UPDATE
+SET
+    title           = EXCLUDED.title,
+    description     = EXCLUDED.description,
+    estimated_hours = EXCLUDED.estimated_hours,
+    order_index     = EXCLUDED.order_index
+RETURNING *;
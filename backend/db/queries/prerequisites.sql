@@ -0,0 +1,107 @@
+-- A module counts as completed once the user has completed every one of its
+-- published lessons. Prerequisites that learners can't see (drafts, archived
+-- content) never block anyone.
+
+-- name: ListUnmetModulePrerequisites :many
+SELECT
+    mp.module_id,
+    rm.id    AS required_module_id,
+    rm.slug  AS required_module_slug,
+    rm.title AS required_module_title
+FROM module_prerequisites mp
+JOIN modules rm ON rm.id = mp.required_module_id
+WHERE (rm.status = 'published' OR (rm.status = 'scheduled' AND rm.publish_at <= NOW()))
+  AND EXISTS (
+      SELECT 1
+      FROM lessons l
+      WHERE l.module_id = rm.id
+        AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+        AND NOT EXISTS (
+            SELECT 1 FROM user_lesson_progress ulp
+            WHERE ulp.user_id = $1 AND ulp.lesson_id = l.id
+        )
+  )
+ORDER BY rm.order_index ASC;
+
+-- name: GetUnmetModulePrerequisites :many
+SELECT
+    rm.id    AS required_module_id,
+    rm.slug  AS required_module_slug,
+    rm.title AS required_module_title
+FROM module_prerequisites mp
+JOIN modules rm ON rm.id = mp.required_module_id
+WHERE mp.module_id = $1
+  AND (rm.status = 'published' OR (rm.status = 'scheduled' AND rm.publish_at <= NOW()))
+  AND EXISTS (
+      SELECT 1
+      FROM lessons l
+      WHERE l.module_id = rm.id
+        AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+        AND NOT EXISTS (
+            SELECT 1 FROM user_lesson_progress ulp
+            WHERE ulp.user_id = $2 AND ulp.lesson_id = l.id
+        )
+  )
+ORDER BY rm.order_index ASC;
+
+-- name: GetUnmetLessonPrerequisites :many
+SELECT
+    rl.id    AS required_lesson_id,
+    rl.slug  AS required_lesson_slug,
+    lr.title AS required_lesson_title,
+    m.slug   AS module_slug
+FROM lesson_prerequisites lp
+JOIN lessons rl ON rl.id = lp.required_lesson_id
+JOIN lesson_revisions lr ON lr.id = rl.published_revision_id
+JOIN modules m ON m.id = rl.module_id
+WHERE lp.lesson_id = $1
+  AND (rl.status = 'published' OR (rl.status = 'scheduled' AND rl.publish_at <= NOW()))
+  AND NOT EXISTS (
+      SELECT 1 FROM user_lesson_progress ulp
+      WHERE ulp.user_id = $2 AND ulp.lesson_id = rl.id
+  )
+ORDER BY m.order_index ASC, rl.order_index ASC;
+
+-- name: ListModulePrerequisites :many
+SELECT m.*
+FROM module_prerequisites mp
+JOIN modules m ON m.id = mp.required_module_id
+WHERE mp.module_id = $1
+ORDER BY m.order_index ASC;
+
+-- name: DeleteModulePrerequisites :exec
+DELETE FROM module_prerequisites
+WHERE module_id = $1;
+
+-- name: AddModulePrerequisite :exec
+INSERT INTO module_prerequisites (module_id, required_module_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListModulePrerequisiteEdges :many
+SELECT module_id, required_module_id FROM module_prerequisites;
+
+-- name: ListLessonPrerequisites :many
+SELECT
+    l.id,
+    l.module_id,
+    l.title,
+    l.slug,
+    m.slug AS module_slug
+FROM lesson_prerequisites lp
+JOIN lessons l ON l.id = lp.required_lesson_id
+JOIN modules m ON m.id = l.module_id
+WHERE lp.lesson_id = $1
+ORDER BY m.order_index ASC, l.order_index ASC;
+
+-- name: DeleteLessonPrerequisites :exec
+DELETE FROM lesson_prerequisites
+WHERE lesson_id = $1;
+
+-- name: AddLessonPrerequisite :exec
+INSERT INTO lesson_prerequisites (lesson_id, required_lesson_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListLessonPrerequisiteEdges :many
+SELECT lesson_id, required_lesson_id FROM lesson_prerequisites;
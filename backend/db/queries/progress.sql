@@ -3,6 +3,24 @@ INSERT INTO user_lesson_progress (user_id, lesson_id)
 VALUES ($1, $2)
 ON CONFLICT (user_id, lesson_id) DO NOTHING;
 
+-- name: UnmarkLessonComplete :execrows
+DELETE FROM user_lesson_progress
+WHERE user_id = $1 AND lesson_id = $2;
+
+-- name: ResetModuleLessonProgress :execrows
+-- Archives exactly the rows it deletes, so a lesson completed concurrently is
+-- either both archived and deleted or left alone.
+WITH d AS (
+    DELETE FROM user_lesson_progress ulp
+    USING lessons l
+    WHERE l.id = ulp.lesson_id
+      AND ulp.user_id = sqlc.arg(user_id)
+      AND l.module_id = sqlc.arg(module_id)
+    RETURNING ulp.user_id, ulp.lesson_id, ulp.completed_at
+)
+INSERT INTO lesson_progress_archive (user_id, lesson_id, completed_at)
+SELECT user_id, lesson_id, completed_at FROM d;
+
 -- name: GetCompletedLessonIDs :many
 SELECT lesson_id FROM user_lesson_progress
 WHERE user_id = $1;
@@ -12,3 +30,84 @@ SELECT COUNT(ulp.lesson_id)
 FROM user_lesson_progress ulp
 JOIN lessons l ON l.id = ulp.lesson_id
 WHERE ulp.user_id = $1 AND l.module_id = $2;
+
+-- name: ExportUserLessonProgress :many
+SELECT
+    m.slug AS module_slug,
+    l.slug AS lesson_slug,
+    l.title AS lesson_title,
+    ulp.completed_at
+FROM user_lesson_progress ulp
+JOIN lessons l ON l.id = ulp.lesson_id
+JOIN modules m ON m.id = l.module_id
+WHERE ulp.user_id = $1
+ORDER BY ulp.completed_at ASC;
+
+-- name: ExportUserArchivedLessonProgress :many
+SELECT
+    m.slug AS module_slug,
+    l.slug AS lesson_slug,
+    l.title AS lesson_title,
+    lpa.completed_at,
+    lpa.archived_at
+FROM lesson_progress_archive lpa
+JOIN lessons l ON l.id = lpa.lesson_id
+JOIN modules m ON m.id = l.module_id
+WHERE lpa.user_id = $1
+ORDER BY lpa.archived_at ASC, lpa.completed_at ASC;
+
+-- name: ListModuleProgress :many
+-- One row per module with the user's progress through its published lessons,
+-- its skills and its assignment, plus the first published lesson they haven't
+-- completed yet.
+WITH lesson_counts AS (
+    SELECT
+        l.module_id,
+        COUNT(*)             AS total,
+        COUNT(ulp.lesson_id) AS completed
+    FROM lessons l
+    LEFT JOIN user_lesson_progress ulp ON ulp.lesson_id = l.id AND ulp.user_id = sqlc.arg(user_id)
+    WHERE l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW())
+    GROUP BY l.module_id
+),
+skill_counts AS (
+    SELECT
+        s.module_id,
+        COUNT(*)            AS total,
+        COUNT(usp.skill_id) AS completed
+    FROM skills s
+    LEFT JOIN user_skill_progress usp ON usp.skill_id = s.id AND usp.user_id = sqlc.arg(user_id)
+    GROUP BY s.module_id
+),
+next_lessons AS (
+    SELECT DISTINCT ON (l.module_id)
+        l.module_id,
+        l.id,
+        l.slug,
+        lr.title
+    FROM lessons l
+    JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+    WHERE (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+      AND NOT EXISTS (
+          SELECT 1 FROM user_lesson_progress ulp
+          WHERE ulp.user_id = sqlc.arg(user_id) AND ulp.lesson_id = l.id
+      )
+    ORDER BY l.module_id, l.order_index ASC
+)
+SELECT
+    m.id                           AS module_id,
+    COALESCE(lc.total, 0)::int     AS total_lessons,
+    COALESCE(lc.completed, 0)::int AS completed_lessons,
+    COALESCE(sc.total, 0)::int     AS total_skills,
+    COALESCE(sc.completed, 0)::int AS completed_skills,
+    (a.id IS NOT NULL)::bool       AS has_assignment,
+    sub.status                     AS submission_status,
+    nl.id                          AS next_lesson_id,
+    nl.slug                        AS next_lesson_slug,
+    nl.title                       AS next_lesson_title
+FROM modules m
+LEFT JOIN lesson_counts lc ON lc.module_id = m.id
+LEFT JOIN skill_counts sc ON sc.module_id = m.id
+LEFT JOIN assignments a ON a.module_id = m.id
+LEFT JOIN submissions sub ON sub.assignment_id = a.id AND sub.user_id = sqlc.arg(user_id)
+LEFT JOIN next_lessons nl ON nl.module_id = m.id;
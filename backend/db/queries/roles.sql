@@ -0,0 +1,18 @@
+-- name: UserHasPermission :one
+-- Goes through users.role rather than the role in the caller's token, so a
+-- role change takes effect on the next request.
+SELECT EXISTS (
+    SELECT 1 FROM users u
+    JOIN role_permissions rp ON rp.role = u.role
+    WHERE u.id = sqlc.arg(user_id) AND rp.permission = sqlc.arg(permission)
+);
+
+-- name: ListRoles :many
+SELECT
+    r.name,
+    r.description,
+    COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')::TEXT[] AS permissions
+FROM roles r
+LEFT JOIN role_permissions rp ON rp.role = r.name
+GROUP BY r.name, r.description
+ORDER BY r.name;
@@ -0,0 +1,57 @@
+-- name: SearchContent :many
+-- Searches published modules, lessons and skills, best match first. Lesson
+-- excerpts are only built for preview lessons or when include_excerpts is set.
+WITH search AS (
+    SELECT websearch_to_tsquery('english', sqlc.arg(query)::text) AS q
+)
+SELECT hits.kind, hits.id, hits.module_slug, hits.lesson_slug, hits.title, hits.snippet, hits.rank
+FROM (
+    SELECT
+        'module'::text AS kind,
+        m.id,
+        m.slug AS module_slug,
+        ''::text AS lesson_slug,
+        ts_headline('english', m.title, search.q, sqlc.arg(title_options)::text)::text AS title,
+        ts_headline('english', m.description, search.q, sqlc.arg(headline_options)::text)::text AS snippet,
+        ts_rank(module_search_vector(m.title, m.description), search.q) AS rank
+    FROM modules m, search
+    WHERE module_search_vector(m.title, m.description) @@ search.q
+      AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+
+    UNION ALL
+
+    SELECT
+        'lesson'::text,
+        l.id,
+        m.slug,
+        l.slug,
+        ts_headline('english', lr.title, search.q, sqlc.arg(title_options)::text),
+        CASE WHEN sqlc.arg(include_excerpts)::boolean OR l.is_preview
+            THEN ts_headline('english', lr.content, search.q, sqlc.arg(headline_options)::text)
+            ELSE ''
+        END,
+        ts_rank(lesson_search_vector(lr.title, lr.content), search.q)
+    FROM lessons l
+    JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+    JOIN modules m ON m.id = l.module_id, search
+    WHERE lesson_search_vector(lr.title, lr.content) @@ search.q
+      AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+      AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+
+    UNION ALL
+
+    SELECT
+        'skill'::text,
+        s.id,
+        m.slug,
+        ''::text,
+        ts_headline('english', s.skill_name, search.q, sqlc.arg(title_options)::text),
+        ''::text,
+        ts_rank(skill_search_vector(s.skill_name), search.q)
+    FROM skills s
+    JOIN modules m ON m.id = s.module_id, search
+    WHERE skill_search_vector(s.skill_name) @@ search.q
+      AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+) hits
+ORDER BY hits.rank DESC, hits.title ASC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
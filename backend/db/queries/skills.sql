@@ -13,6 +13,72 @@ INSERT INTO user_skill_progress (user_id, skill_id)
 VALUES ($1, $2)
 ON CONFLICT (user_id, skill_id) DO NOTHING;
 
+-- name: UnmarkSkillComplete :execrows
+DELETE FROM user_skill_progress
+WHERE user_id = $1 AND skill_id = $2;
+
+-- name: ResetModuleSkillProgress :execrows
+-- Like ResetModuleLessonProgress, archives exactly the rows it deletes.
+WITH d AS (
+    DELETE FROM user_skill_progress usp
+    USING skills s
+    WHERE s.id = usp.skill_id
+      AND usp.user_id = sqlc.arg(user_id)
+      AND s.module_id = sqlc.arg(module_id)
+    RETURNING usp.user_id, usp.skill_id, usp.completed_at
+)
+INSERT INTO skill_progress_archive (user_id, skill_id, completed_at)
+SELECT user_id, skill_id, completed_at FROM d;
+
 -- name: GetCompletedSkillIDs :many
 SELECT skill_id FROM user_skill_progress
 WHERE user_id = $1;
+
+-- name: ExportUserSkillProgress :many
+SELECT
+    m.slug AS module_slug,
+    s.skill_name,
+    usp.completed_at
+FROM user_skill_progress usp
+JOIN skills s ON s.id = usp.skill_id
+JOIN modules m ON m.id = s.module_id
+WHERE usp.user_id = $1
+ORDER BY usp.completed_at ASC;
+
+-- name: ExportUserArchivedSkillProgress :many
+SELECT
+    m.slug AS module_slug,
+    s.skill_name,
+    spa.completed_at,
+    spa.archived_at
+FROM skill_progress_archive spa
+JOIN skills s ON s.id = spa.skill_id
+JOIN modules m ON m.id = s.module_id
+WHERE spa.user_id = $1
+ORDER BY spa.archived_at ASC, spa.completed_at ASC;
+
+-- name: CreateSkill :one
+INSERT INTO skills (module_id, skill_name, order_index)
+VALUES ($1, $2, (SELECT COALESCE(MAX(order_index), 0) + 1 FROM skills WHERE module_id = $1))
+RETURNING *;
+
+-- name: UpdateSkill :one
+UPDATE skills
+SET skill_name = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: CountSkillProgress :one
+-- Learners who completed the skill, now or before a module reset.
+SELECT
+    (SELECT COUNT(*) FROM user_skill_progress usp WHERE usp.skill_id = $1) +
+    (SELECT COUNT(*) FROM skill_progress_archive spa WHERE spa.skill_id = $1);
+
+-- name: DeleteSkill :execrows
+DELETE FROM skills
+WHERE id = $1;
+
+-- name: SetSkillOrderIndex :exec
+UPDATE skills
+SET order_index = $2
+WHERE id = $1;
@@ -31,3 +31,19 @@ RETURNING *;
 SELECT * FROM submissions
 WHERE assignment_id = $1 AND user_id = $2
 LIMIT 1;
+
+-- name: ExportUserSubmissions :many
+SELECT
+    m.slug AS module_slug,
+    a.title AS assignment_title,
+    s.github_url,
+    s.written_answers,
+    s.status,
+    s.feedback,
+    s.submitted_at,
+    s.reviewed_at
+FROM submissions s
+JOIN assignments a ON a.id = s.assignment_id
+JOIN modules m ON m.id = a.module_id
+WHERE s.user_id = $1
+ORDER BY s.submitted_at ASC;
@@ -36,3 +36,92 @@ RETURNING *;
 SELECT subscription_status FROM users
 WHERE id = $1
 LIMIT 1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2
+WHERE id = $1;
+
+-- name: MarkUserEmailVerified :execrows
+UPDATE users
+SET email_verified_at = NOW()
+WHERE id = $1 AND email = $2 AND email_verified_at IS NULL;
+
+-- name: GetUserByGitHubID :one
+SELECT * FROM users
+WHERE github_id = $1
+LIMIT 1;
+
+-- name: CreateGitHubUser :one
+INSERT INTO users (email, password_hash, name, github_id, github_login, email_verified_at)
+VALUES ($1, '', $2, $3, $4, NOW())
+RETURNING *;
+
+-- name: LinkUserGitHub :one
+UPDATE users
+SET
+    github_id    = $2,
+    github_login = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: RecordFailedLogin :one
+-- Failures older than window_start no longer count: the counter starts over.
+UPDATE users
+SET
+    failed_login_attempts = CASE
+        WHEN last_failed_login_at IS NULL OR last_failed_login_at < sqlc.arg(window_start) THEN 1
+        ELSE failed_login_attempts + 1
+    END,
+    last_failed_login_at  = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING failed_login_attempts;
+
+-- name: LockUser :exec
+UPDATE users
+SET locked_until = $2
+WHERE id = $1;
+
+-- name: ResetFailedLogins :exec
+UPDATE users
+SET
+    failed_login_attempts = 0,
+    locked_until          = NULL
+WHERE id = $1;
+
+-- name: UpdateUserName :one
+UPDATE users
+SET name = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: IsKnownTimezone :one
+-- Activity is bucketed into days by Postgres, so a time zone has to be in its
+-- tz database as well as Go's.
+SELECT EXISTS (
+    SELECT 1 FROM pg_catalog.pg_timezone_names WHERE name = sqlc.arg(name)::text
+);
+
+-- name: UpdateUserTimezone :one
+UPDATE users
+SET timezone = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ChangeUserEmail :one
+UPDATE users
+SET
+    email             = sqlc.arg(new_email),
+    email_verified_at = NOW()
+WHERE id = sqlc.arg(id) AND email = sqlc.arg(current_email)
+RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE id = $1
+RETURNING *;
@@ -4,46 +4,51 @@
 -- ============================================================
 
 -- ── Modules ──────────────────────────────────────────────────
-INSERT INTO modules (title, slug, description, order_index, estimated_hours) VALUES
+INSERT INTO modules (title, slug, description, order_index, estimated_hours, status) VALUES
 (
     'Go Concurrency & Graceful Shutdown',
     'go-concurrency',
     'Master production-grade concurrency patterns: worker pools, context propagation, backpressure, goroutine leak prevention, and graceful shutdown. Build systems that handle load and fail cleanly.',
     1,
-    8.0
+    8.0,
+    'published'
 ),
 (
     'Distributed Systems Fundamentals',
     'distributed-systems',
     'Idempotency, retries with exponential backoff, circuit breakers, rate limiting, message queues, and the difference between exactly-once vs at-least-once delivery.',
     2,
-    10.0
+    10.0,
+    'published'
 ),
 (
     'Reliability & Observability',
     'reliability',
     'Structured logging, distributed tracing, SLOs, error budgets, deploy strategies (blue/green, canary), and building systems that are debuggable in production.',
     3,
-    8.0
+    8.0,
+    'published'
 ),
 (
     'Architecture & Systems Thinking',
     'architecture',
     'Tradeoff simulations, CAP theorem in practice, data modeling under load, scaling decisions, and how senior engineers think about system design.',
     4,
-    12.0
+    12.0,
+    'published'
 )
 ON CONFLICT (slug) DO NOTHING;
 
 -- ── Lessons: Module 1 — Go Concurrency ───────────────────────
-INSERT INTO lessons (module_id, title, slug, content, order_index, estimated_minutes)
+INSERT INTO lessons (module_id, title, slug, content, order_index, estimated_minutes, status)
 SELECT
     m.id,
     l.title,
     l.slug,
     l.content,
     l.order_index,
-    l.estimated_minutes
+    l.estimated_minutes,
+    'published'
 FROM modules m
 CROSS JOIN (VALUES
     (
@@ -350,6 +355,25 @@ Watch `pg_stat_activity` in production. If you see hundreds of `idle in transact
 WHERE m.slug = 'go-concurrency'
 ON CONFLICT (module_id, slug) DO NOTHING;
 
+-- ── Lesson revisions ─────────────────────────────────────────
+-- Learners read the published revision, so each seeded lesson gets its content
+-- as revision 1 and has it published.
+INSERT INTO lesson_revisions (lesson_id, revision_number, title, content)
+SELECT l.id, 1, l.title, l.content
+FROM lessons l
+JOIN modules m ON m.id = l.module_id
+WHERE m.slug = 'go-concurrency'
+ON CONFLICT (lesson_id, revision_number) DO NOTHING;
+
+UPDATE lessons l
+SET published_revision_id = lr.id
+FROM lesson_revisions lr, modules m
+WHERE lr.lesson_id = l.id
+  AND lr.revision_number = 1
+  AND m.id = l.module_id
+  AND m.slug = 'go-concurrency'
+  AND l.published_revision_id IS NULL;
+
 -- ── Skills: Module 1 ─────────────────────────────────────────
 INSERT INTO skills (module_id, skill_name, order_index)
 SELECT m.id, s.skill_name, s.order_index
@@ -377,3 +401,15 @@ SELECT
 FROM modules m
 WHERE m.slug = 'go-concurrency'
 ON CONFLICT (module_id) DO NOTHING;
+
+-- ── Prerequisites: each module requires the one before it ────
+INSERT INTO module_prerequisites (module_id, required_module_id)
+SELECT m.id, req.id
+FROM (VALUES
+    ('distributed-systems', 'go-concurrency'),
+    ('reliability', 'distributed-systems'),
+    ('architecture', 'reliability')
+) AS p(slug, required_slug)
+JOIN modules m ON m.slug = p.slug
+JOIN modules req ON req.slug = p.required_slug
+ON CONFLICT DO NOTHING;
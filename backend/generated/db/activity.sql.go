@@ -0,0 +1,84 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: activity.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listActivityByDay = `-- name: ListActivityByDay :many
+WITH events AS (
+    SELECT 'lesson'::text AS kind, ulp.completed_at AS at
+    FROM user_lesson_progress ulp
+    WHERE ulp.user_id = $2
+    UNION ALL
+    SELECT 'lesson', lpa.completed_at
+    FROM lesson_progress_archive lpa
+    WHERE lpa.user_id = $2
+    UNION ALL
+    SELECT 'skill', usp.completed_at
+    FROM user_skill_progress usp
+    WHERE usp.user_id = $2
+    UNION ALL
+    SELECT 'skill', spa.completed_at
+    FROM skill_progress_archive spa
+    WHERE spa.user_id = $2
+    UNION ALL
+    SELECT 'submission', s.submitted_at
+    FROM submissions s
+    WHERE s.user_id = $2
+)
+SELECT
+    (e.at AT TIME ZONE $1::text)::date AS day,
+    COUNT(*) FILTER (WHERE e.kind = 'lesson')::int     AS lessons,
+    COUNT(*) FILTER (WHERE e.kind = 'skill')::int      AS skills,
+    COUNT(*) FILTER (WHERE e.kind = 'submission')::int AS submissions
+FROM events e
+GROUP BY day
+ORDER BY day ASC
+`
+
+type ListActivityByDayParams struct {
+	Timezone string    `json:"timezone"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+type ListActivityByDayRow struct {
+	Day         pgtype.Date `json:"day"`
+	Lessons     int32       `json:"lessons"`
+	Skills      int32       `json:"skills"`
+	Submissions int32       `json:"submissions"`
+}
+
+// Per-day activity counts over the user's whole history, with days taken in
+// the given time zone. Completions cleared by a module reset still count.
+func (q *Queries) ListActivityByDay(ctx context.Context, arg ListActivityByDayParams) ([]ListActivityByDayRow, error) {
+	rows, err := q.db.Query(ctx, listActivityByDay, arg.Timezone, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActivityByDayRow{}
+	for rows.Next() {
+		var i ListActivityByDayRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.Lessons,
+			&i.Skills,
+			&i.Submissions,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
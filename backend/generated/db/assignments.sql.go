@@ -9,8 +9,22 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteAssignmentByModuleID = `-- name: DeleteAssignmentByModuleID :execrows
+DELETE FROM assignments
+WHERE module_id = $1
+`
+
+func (q *Queries) DeleteAssignmentByModuleID(ctx context.Context, moduleID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAssignmentByModuleID, moduleID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getAssignmentByID = `-- name: GetAssignmentByID :one
 SELECT id, module_id, title, description, rubric, estimated_hours, created_at, updated_at FROM assignments
 WHERE id = $1
@@ -54,3 +68,45 @@ func (q *Queries) GetAssignmentByModuleID(ctx context.Context, moduleID uuid.UUI
 	)
 	return i, err
 }
+
+const upsertAssignment = `-- name: UpsertAssignment :one
+INSERT INTO assignments (module_id, title, description, rubric, estimated_hours)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (module_id) DO UPDATE
+SET
+    title           = EXCLUDED.title,
+    description     = EXCLUDED.description,
+    rubric          = EXCLUDED.rubric,
+    estimated_hours = EXCLUDED.estimated_hours
+RETURNING id, module_id, title, description, rubric, estimated_hours, created_at, updated_at
+`
+
+type UpsertAssignmentParams struct {
+	ModuleID       uuid.UUID      `json:"module_id"`
+	Title          string         `json:"title"`
+	Description    string         `json:"description"`
+	Rubric         string         `json:"rubric"`
+	EstimatedHours pgtype.Numeric `json:"estimated_hours"`
+}
+
+func (q *Queries) UpsertAssignment(ctx context.Context, arg UpsertAssignmentParams) (Assignment, error) {
+	row := q.db.QueryRow(ctx, upsertAssignment,
+		arg.ModuleID,
+		arg.Title,
+		arg.Description,
+		arg.Rubric,
+		arg.EstimatedHours,
+	)
+	var i Assignment
+	err := row.Scan(
+		&i.ID,
+		&i.ModuleID,
+		&i.Title,
+		&i.Description,
+		&i.Rubric,
+		&i.EstimatedHours,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
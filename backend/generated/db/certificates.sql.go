@@ -0,0 +1,101 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: certificates.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getCertificate = `-- name: GetCertificate :one
+SELECT id, user_id, module_id, recipient_name, module_title, issued_at FROM certificates
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetCertificate(ctx context.Context, id uuid.UUID) (Certificate, error) {
+	row := q.db.QueryRow(ctx, getCertificate, id)
+	var i Certificate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ModuleID,
+		&i.RecipientName,
+		&i.ModuleTitle,
+		&i.IssuedAt,
+	)
+	return i, err
+}
+
+const issueCertificate = `-- name: IssueCertificate :one
+INSERT INTO certificates (user_id, module_id, recipient_name, module_title)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, module_id) DO UPDATE
+SET user_id = certificates.user_id
+RETURNING id, user_id, module_id, recipient_name, module_title, issued_at
+`
+
+type IssueCertificateParams struct {
+	UserID        uuid.UUID   `json:"user_id"`
+	ModuleID      pgtype.UUID `json:"module_id"`
+	RecipientName string      `json:"recipient_name"`
+	ModuleTitle   string      `json:"module_title"`
+}
+
+// Issuing again returns the existing certificate unchanged.
+func (q *Queries) IssueCertificate(ctx context.Context, arg IssueCertificateParams) (Certificate, error) {
+	row := q.db.QueryRow(ctx, issueCertificate,
+		arg.UserID,
+		arg.ModuleID,
+		arg.RecipientName,
+		arg.ModuleTitle,
+	)
+	var i Certificate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ModuleID,
+		&i.RecipientName,
+		&i.ModuleTitle,
+		&i.IssuedAt,
+	)
+	return i, err
+}
+
+const listUserCertificates = `-- name: ListUserCertificates :many
+SELECT id, user_id, module_id, recipient_name, module_title, issued_at FROM certificates
+WHERE user_id = $1
+ORDER BY issued_at ASC
+`
+
+func (q *Queries) ListUserCertificates(ctx context.Context, userID uuid.UUID) ([]Certificate, error) {
+	rows, err := q.db.Query(ctx, listUserCertificates, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Certificate{}
+	for rows.Next() {
+		var i Certificate
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ModuleID,
+			&i.RecipientName,
+			&i.ModuleTitle,
+			&i.IssuedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
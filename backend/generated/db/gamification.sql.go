@@ -0,0 +1,210 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: gamification.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addUserBadge = `-- name: AddUserBadge :execrows
+INSERT INTO user_badges (user_id, badge)
+VALUES ($1, $2)
+ON CONFLICT (user_id, badge) DO NOTHING
+`
+
+type AddUserBadgeParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Badge  string    `json:"badge"`
+}
+
+func (q *Queries) AddUserBadge(ctx context.Context, arg AddUserBadgeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addUserBadge, arg.UserID, arg.Badge)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const awardXP = `-- name: AwardXP :execrows
+INSERT INTO xp_events (user_id, source, source_id, xp)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, source, source_id) DO NOTHING
+`
+
+type AwardXPParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Source   XpSource  `json:"source"`
+	SourceID uuid.UUID `json:"source_id"`
+	Xp       int32     `json:"xp"`
+}
+
+func (q *Queries) AwardXP(ctx context.Context, arg AwardXPParams) (int64, error) {
+	result, err := q.db.Exec(ctx, awardXP,
+		arg.UserID,
+		arg.Source,
+		arg.SourceID,
+		arg.Xp,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const exportUserXPEvents = `-- name: ExportUserXPEvents :many
+SELECT source, source_id, xp, awarded_at
+FROM xp_events
+WHERE user_id = $1
+ORDER BY awarded_at ASC
+`
+
+type ExportUserXPEventsRow struct {
+	Source    XpSource           `json:"source"`
+	SourceID  uuid.UUID          `json:"source_id"`
+	Xp        int32              `json:"xp"`
+	AwardedAt pgtype.Timestamptz `json:"awarded_at"`
+}
+
+func (q *Queries) ExportUserXPEvents(ctx context.Context, userID uuid.UUID) ([]ExportUserXPEventsRow, error) {
+	rows, err := q.db.Query(ctx, exportUserXPEvents, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportUserXPEventsRow{}
+	for rows.Next() {
+		var i ExportUserXPEventsRow
+		if err := rows.Scan(
+			&i.Source,
+			&i.SourceID,
+			&i.Xp,
+			&i.AwardedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBadgeStats = `-- name: GetBadgeStats :one
+SELECT
+    (SELECT COUNT(*) FROM user_lesson_progress ulp WHERE ulp.user_id = $1)::int AS lessons_completed,
+    (SELECT COUNT(*) FROM user_skill_progress usp WHERE usp.user_id = $1)::int AS skills_completed,
+    (SELECT COUNT(*) FROM submissions s WHERE s.user_id = $1 AND s.status = 'approved')::int AS approved_submissions,
+    (
+        SELECT COUNT(*)
+        FROM modules m
+        WHERE EXISTS (
+            SELECT 1 FROM lessons l
+            WHERE l.module_id = m.id
+              AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+        )
+          AND NOT EXISTS (
+            SELECT 1 FROM lessons l
+            WHERE l.module_id = m.id
+              AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+              AND NOT EXISTS (
+                  SELECT 1 FROM user_lesson_progress ulp
+                  WHERE ulp.user_id = $1 AND ulp.lesson_id = l.id
+              )
+        )
+    )::int AS modules_completed,
+    (SELECT COALESCE(SUM(xp), 0) FROM xp_events x WHERE x.user_id = $1)::int AS xp
+`
+
+type GetBadgeStatsRow struct {
+	LessonsCompleted    int32 `json:"lessons_completed"`
+	SkillsCompleted     int32 `json:"skills_completed"`
+	ApprovedSubmissions int32 `json:"approved_submissions"`
+	ModulesCompleted    int32 `json:"modules_completed"`
+	Xp                  int32 `json:"xp"`
+}
+
+// The counters badge rules are evaluated against. A module counts as finished
+// once every one of its published lessons is completed.
+func (q *Queries) GetBadgeStats(ctx context.Context, userID uuid.UUID) (GetBadgeStatsRow, error) {
+	row := q.db.QueryRow(ctx, getBadgeStats, userID)
+	var i GetBadgeStatsRow
+	err := row.Scan(
+		&i.LessonsCompleted,
+		&i.SkillsCompleted,
+		&i.ApprovedSubmissions,
+		&i.ModulesCompleted,
+		&i.Xp,
+	)
+	return i, err
+}
+
+const getUserXP = `-- name: GetUserXP :one
+SELECT COALESCE(SUM(xp), 0)::int AS xp
+FROM xp_events
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserXP(ctx context.Context, userID uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, getUserXP, userID)
+	var xp int32
+	err := row.Scan(&xp)
+	return xp, err
+}
+
+const listUserBadges = `-- name: ListUserBadges :many
+SELECT badge, earned_at
+FROM user_badges
+WHERE user_id = $1
+ORDER BY earned_at ASC
+`
+
+type ListUserBadgesRow struct {
+	Badge    string             `json:"badge"`
+	EarnedAt pgtype.Timestamptz `json:"earned_at"`
+}
+
+func (q *Queries) ListUserBadges(ctx context.Context, userID uuid.UUID) ([]ListUserBadgesRow, error) {
+	rows, err := q.db.Query(ctx, listUserBadges, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserBadgesRow{}
+	for rows.Next() {
+		var i ListUserBadgesRow
+		if err := rows.Scan(&i.Badge, &i.EarnedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeXP = `-- name: RevokeXP :execrows
+DELETE FROM xp_events
+WHERE user_id = $1 AND source = $2 AND source_id = $3
+`
+
+type RevokeXPParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Source   XpSource  `json:"source"`
+	SourceID uuid.UUID `json:"source_id"`
+}
+
+func (q *Queries) RevokeXP(ctx context.Context, arg RevokeXPParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeXP, arg.UserID, arg.Source, arg.SourceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,121 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: impersonation.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createImpersonationSession = `-- name: CreateImpersonationSession :one
+INSERT INTO impersonation_sessions (admin_id, target_user_id, reason, ip_address, user_agent, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, admin_id, target_user_id, reason, ip_address, user_agent, started_at, expires_at, ended_at
+`
+
+type CreateImpersonationSessionParams struct {
+	AdminID      pgtype.UUID        `json:"admin_id"`
+	TargetUserID pgtype.UUID        `json:"target_user_id"`
+	Reason       string             `json:"reason"`
+	IpAddress    string             `json:"ip_address"`
+	UserAgent    string             `json:"user_agent"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateImpersonationSession(ctx context.Context, arg CreateImpersonationSessionParams) (ImpersonationSession, error) {
+	row := q.db.QueryRow(ctx, createImpersonationSession,
+		arg.AdminID,
+		arg.TargetUserID,
+		arg.Reason,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.ExpiresAt,
+	)
+	var i ImpersonationSession
+	err := row.Scan(
+		&i.ID,
+		&i.AdminID,
+		&i.TargetUserID,
+		&i.Reason,
+		&i.IpAddress,
+		&i.UserAgent,
+		&i.StartedAt,
+		&i.ExpiresAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const endImpersonationSession = `-- name: EndImpersonationSession :execrows
+UPDATE impersonation_sessions
+SET ended_at = NOW()
+WHERE id = $1 AND ended_at IS NULL
+`
+
+func (q *Queries) EndImpersonationSession(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, endImpersonationSession, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const isImpersonationSessionActive = `-- name: IsImpersonationSessionActive :one
+SELECT EXISTS (
+    SELECT 1 FROM impersonation_sessions
+    WHERE id = $1 AND ended_at IS NULL AND expires_at > NOW()
+)
+`
+
+func (q *Queries) IsImpersonationSessionActive(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isImpersonationSessionActive, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listImpersonationSessions = `-- name: ListImpersonationSessions :many
+SELECT id, admin_id, target_user_id, reason, ip_address, user_agent, started_at, expires_at, ended_at FROM impersonation_sessions
+ORDER BY started_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListImpersonationSessionsParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListImpersonationSessions(ctx context.Context, arg ListImpersonationSessionsParams) ([]ImpersonationSession, error) {
+	rows, err := q.db.Query(ctx, listImpersonationSessions, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ImpersonationSession{}
+	for rows.Next() {
+		var i ImpersonationSession
+		if err := rows.Scan(
+			&i.ID,
+			&i.AdminID,
+			&i.TargetUserID,
+			&i.Reason,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.StartedAt,
+			&i.ExpiresAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,171 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: lesson_revisions.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createLessonRevision = `-- name: CreateLessonRevision :one
+INSERT INTO lesson_revisions (lesson_id, revision_number, title, content, author_id)
+VALUES (
+    $1,
+    (SELECT COALESCE(MAX(revision_number), 0) + 1 FROM lesson_revisions WHERE lesson_id = $1),
+    $2,
+    $3,
+    $4
+)
+RETURNING id, lesson_id, revision_number, title, content, author_id, created_at
+`
+
+type CreateLessonRevisionParams struct {
+	LessonID uuid.UUID   `json:"lesson_id"`
+	Title    string      `json:"title"`
+	Content  string      `json:"content"`
+	AuthorID pgtype.UUID `json:"author_id"`
+}
+
+func (q *Queries) CreateLessonRevision(ctx context.Context, arg CreateLessonRevisionParams) (LessonRevision, error) {
+	row := q.db.QueryRow(ctx, createLessonRevision,
+		arg.LessonID,
+		arg.Title,
+		arg.Content,
+		arg.AuthorID,
+	)
+	var i LessonRevision
+	err := row.Scan(
+		&i.ID,
+		&i.LessonID,
+		&i.RevisionNumber,
+		&i.Title,
+		&i.Content,
+		&i.AuthorID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLatestLessonRevision = `-- name: GetLatestLessonRevision :one
+SELECT id, lesson_id, revision_number, title, content, author_id, created_at FROM lesson_revisions
+WHERE lesson_id = $1
+ORDER BY revision_number DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestLessonRevision(ctx context.Context, lessonID uuid.UUID) (LessonRevision, error) {
+	row := q.db.QueryRow(ctx, getLatestLessonRevision, lessonID)
+	var i LessonRevision
+	err := row.Scan(
+		&i.ID,
+		&i.LessonID,
+		&i.RevisionNumber,
+		&i.Title,
+		&i.Content,
+		&i.AuthorID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLessonRevision = `-- name: GetLessonRevision :one
+SELECT id, lesson_id, revision_number, title, content, author_id, created_at FROM lesson_revisions
+WHERE lesson_id = $1 AND revision_number = $2
+LIMIT 1
+`
+
+type GetLessonRevisionParams struct {
+	LessonID       uuid.UUID `json:"lesson_id"`
+	RevisionNumber int32     `json:"revision_number"`
+}
+
+func (q *Queries) GetLessonRevision(ctx context.Context, arg GetLessonRevisionParams) (LessonRevision, error) {
+	row := q.db.QueryRow(ctx, getLessonRevision, arg.LessonID, arg.RevisionNumber)
+	var i LessonRevision
+	err := row.Scan(
+		&i.ID,
+		&i.LessonID,
+		&i.RevisionNumber,
+		&i.Title,
+		&i.Content,
+		&i.AuthorID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLessonRevisionByID = `-- name: GetLessonRevisionByID :one
+SELECT id, lesson_id, revision_number, title, content, author_id, created_at FROM lesson_revisions
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetLessonRevisionByID(ctx context.Context, id uuid.UUID) (LessonRevision, error) {
+	row := q.db.QueryRow(ctx, getLessonRevisionByID, id)
+	var i LessonRevision
+	err := row.Scan(
+		&i.ID,
+		&i.LessonID,
+		&i.RevisionNumber,
+		&i.Title,
+		&i.Content,
+		&i.AuthorID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listLessonRevisions = `-- name: ListLessonRevisions :many
+SELECT
+    lr.id,
+    lr.revision_number,
+    lr.title,
+    lr.author_id,
+    u.name AS author_name,
+    lr.created_at
+FROM lesson_revisions lr
+LEFT JOIN users u ON u.id = lr.author_id
+WHERE lr.lesson_id = $1
+ORDER BY lr.revision_number DESC
+`
+
+type ListLessonRevisionsRow struct {
+	ID             uuid.UUID          `json:"id"`
+	RevisionNumber int32              `json:"revision_number"`
+	Title          string             `json:"title"`
+	AuthorID       pgtype.UUID        `json:"author_id"`
+	AuthorName     *string            `json:"author_name"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListLessonRevisions(ctx context.Context, lessonID uuid.UUID) ([]ListLessonRevisionsRow, error) {
+	rows, err := q.db.Query(ctx, listLessonRevisions, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLessonRevisionsRow{}
+	for rows.Next() {
+		var i ListLessonRevisionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RevisionNumber,
+			&i.Title,
+			&i.AuthorID,
+			&i.AuthorName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
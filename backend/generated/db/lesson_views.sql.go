@@ -0,0 +1,216 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: lesson_views.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const exportUserLessonViews = `-- name: ExportUserLessonViews :many
+SELECT
+    m.slug AS module_slug,
+    l.slug AS lesson_slug,
+    l.title AS lesson_title,
+    lv.started_at,
+    lv.last_viewed_at,
+    lv.scroll_position,
+    lv.section
+FROM lesson_views lv
+JOIN lessons l ON l.id = lv.lesson_id
+JOIN modules m ON m.id = l.module_id
+WHERE lv.user_id = $1
+ORDER BY lv.started_at ASC
+`
+
+type ExportUserLessonViewsRow struct {
+	ModuleSlug     string             `json:"module_slug"`
+	LessonSlug     string             `json:"lesson_slug"`
+	LessonTitle    string             `json:"lesson_title"`
+	StartedAt      pgtype.Timestamptz `json:"started_at"`
+	LastViewedAt   pgtype.Timestamptz `json:"last_viewed_at"`
+	ScrollPosition float64            `json:"scroll_position"`
+	Section        *string            `json:"section"`
+}
+
+func (q *Queries) ExportUserLessonViews(ctx context.Context, userID uuid.UUID) ([]ExportUserLessonViewsRow, error) {
+	rows, err := q.db.Query(ctx, exportUserLessonViews, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportUserLessonViewsRow{}
+	for rows.Next() {
+		var i ExportUserLessonViewsRow
+		if err := rows.Scan(
+			&i.ModuleSlug,
+			&i.LessonSlug,
+			&i.LessonTitle,
+			&i.StartedAt,
+			&i.LastViewedAt,
+			&i.ScrollPosition,
+			&i.Section,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNextRecommendedLesson = `-- name: GetNextRecommendedLesson :one
+SELECT
+    l.id     AS lesson_id,
+    l.slug   AS lesson_slug,
+    lr.title AS lesson_title,
+    m.slug   AS module_slug,
+    m.title  AS module_title
+FROM lessons l
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+JOIN modules m ON m.id = l.module_id
+WHERE (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+  AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+  AND l.id IS DISTINCT FROM $1
+  AND NOT EXISTS (
+      SELECT 1 FROM user_lesson_progress ulp
+      WHERE ulp.user_id = $2 AND ulp.lesson_id = l.id
+  )
+ORDER BY m.order_index ASC, l.order_index ASC
+LIMIT 1
+`
+
+type GetNextRecommendedLessonParams struct {
+	ExcludeLessonID pgtype.UUID `json:"exclude_lesson_id"`
+	UserID          uuid.UUID   `json:"user_id"`
+}
+
+type GetNextRecommendedLessonRow struct {
+	LessonID    uuid.UUID `json:"lesson_id"`
+	LessonSlug  string    `json:"lesson_slug"`
+	LessonTitle string    `json:"lesson_title"`
+	ModuleSlug  string    `json:"module_slug"`
+	ModuleTitle string    `json:"module_title"`
+}
+
+// The first published lesson, in curriculum order, that the user hasn't
+// completed, skipping the lesson they're already resuming.
+func (q *Queries) GetNextRecommendedLesson(ctx context.Context, arg GetNextRecommendedLessonParams) (GetNextRecommendedLessonRow, error) {
+	row := q.db.QueryRow(ctx, getNextRecommendedLesson, arg.ExcludeLessonID, arg.UserID)
+	var i GetNextRecommendedLessonRow
+	err := row.Scan(
+		&i.LessonID,
+		&i.LessonSlug,
+		&i.LessonTitle,
+		&i.ModuleSlug,
+		&i.ModuleTitle,
+	)
+	return i, err
+}
+
+const getResumeLesson = `-- name: GetResumeLesson :one
+SELECT
+    l.id     AS lesson_id,
+    l.slug   AS lesson_slug,
+    lr.title AS lesson_title,
+    m.slug   AS module_slug,
+    m.title  AS module_title,
+    lv.started_at,
+    lv.last_viewed_at,
+    lv.scroll_position,
+    lv.section
+FROM lesson_views lv
+JOIN lessons l ON l.id = lv.lesson_id
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+JOIN modules m ON m.id = l.module_id
+WHERE lv.user_id = $1
+  AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+  AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+  AND NOT EXISTS (
+      SELECT 1 FROM user_lesson_progress ulp
+      WHERE ulp.user_id = lv.user_id AND ulp.lesson_id = l.id
+  )
+ORDER BY lv.last_viewed_at DESC
+LIMIT 1
+`
+
+type GetResumeLessonRow struct {
+	LessonID       uuid.UUID          `json:"lesson_id"`
+	LessonSlug     string             `json:"lesson_slug"`
+	LessonTitle    string             `json:"lesson_title"`
+	ModuleSlug     string             `json:"module_slug"`
+	ModuleTitle    string             `json:"module_title"`
+	StartedAt      pgtype.Timestamptz `json:"started_at"`
+	LastViewedAt   pgtype.Timestamptz `json:"last_viewed_at"`
+	ScrollPosition float64            `json:"scroll_position"`
+	Section        *string            `json:"section"`
+}
+
+// The most recently viewed published lesson the user hasn't completed.
+func (q *Queries) GetResumeLesson(ctx context.Context, userID uuid.UUID) (GetResumeLessonRow, error) {
+	row := q.db.QueryRow(ctx, getResumeLesson, userID)
+	var i GetResumeLessonRow
+	err := row.Scan(
+		&i.LessonID,
+		&i.LessonSlug,
+		&i.LessonTitle,
+		&i.ModuleSlug,
+		&i.ModuleTitle,
+		&i.StartedAt,
+		&i.LastViewedAt,
+		&i.ScrollPosition,
+		&i.Section,
+	)
+	return i, err
+}
+
+const recordLessonView = `-- name: RecordLessonView :one
+INSERT INTO lesson_views (user_id, lesson_id, scroll_position, section)
+VALUES (
+    $1,
+    $2,
+    COALESCE($3::double precision, 0),
+    $4
+)
+ON CONFLICT (user_id, lesson_id) DO UPDATE
+SET
+    last_viewed_at  = NOW(),
+    scroll_position = COALESCE($3::double precision, lesson_views.scroll_position),
+    section         = COALESCE($4, lesson_views.section)
+RETURNING user_id, lesson_id, started_at, last_viewed_at, scroll_position, section
+`
+
+type RecordLessonViewParams struct {
+	UserID         uuid.UUID `json:"user_id"`
+	LessonID       uuid.UUID `json:"lesson_id"`
+	ScrollPosition *float64  `json:"scroll_position"`
+	Section        *string   `json:"section"`
+}
+
+// Position fields left NULL keep their stored value, so serving a lesson only
+// bumps last_viewed_at.
+func (q *Queries) RecordLessonView(ctx context.Context, arg RecordLessonViewParams) (LessonView, error) {
+	row := q.db.QueryRow(ctx, recordLessonView,
+		arg.UserID,
+		arg.LessonID,
+		arg.ScrollPosition,
+		arg.Section,
+	)
+	var i LessonView
+	err := row.Scan(
+		&i.UserID,
+		&i.LessonID,
+		&i.StartedAt,
+		&i.LastViewedAt,
+		&i.ScrollPosition,
+		&i.Section,
+	)
+	return i, err
+}
@@ -9,10 +9,81 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countLessonProgress = `-- name: CountLessonProgress :one
+SELECT
+    (SELECT COUNT(*) FROM user_lesson_progress ulp WHERE ulp.lesson_id = $1) +
+    (SELECT COUNT(*) FROM lesson_progress_archive lpa WHERE lpa.lesson_id = $1)
+`
+
+// Learners who completed the lesson, now or before a module reset.
+func (q *Queries) CountLessonProgress(ctx context.Context, lessonID uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, countLessonProgress, lessonID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createLesson = `-- name: CreateLesson :one
+INSERT INTO lessons (module_id, title, slug, content, estimated_minutes, is_preview, order_index)
+VALUES ($1, $2, $3, $4, $5, $6, (SELECT COALESCE(MAX(order_index), 0) + 1 FROM lessons WHERE module_id = $1))
+RETURNING id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at, status, publish_at, published_revision_id, is_preview
+`
+
+type CreateLessonParams struct {
+	ModuleID         uuid.UUID `json:"module_id"`
+	Title            string    `json:"title"`
+	Slug             string    `json:"slug"`
+	Content          string    `json:"content"`
+	EstimatedMinutes int32     `json:"estimated_minutes"`
+	IsPreview        bool      `json:"is_preview"`
+}
+
+func (q *Queries) CreateLesson(ctx context.Context, arg CreateLessonParams) (Lesson, error) {
+	row := q.db.QueryRow(ctx, createLesson,
+		arg.ModuleID,
+		arg.Title,
+		arg.Slug,
+		arg.Content,
+		arg.EstimatedMinutes,
+		arg.IsPreview,
+	)
+	var i Lesson
+	err := row.Scan(
+		&i.ID,
+		&i.ModuleID,
+		&i.Title,
+		&i.Slug,
+		&i.Content,
+		&i.OrderIndex,
+		&i.EstimatedMinutes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.PublishedRevisionID,
+		&i.IsPreview,
+	)
+	return i, err
+}
+
+const deleteLesson = `-- name: DeleteLesson :execrows
+DELETE FROM lessons
+WHERE id = $1
+`
+
+func (q *Queries) DeleteLesson(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteLesson, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getLessonByID = `-- name: GetLessonByID :one
-SELECT id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at FROM lessons
+SELECT id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at, status, publish_at, published_revision_id, is_preview FROM lessons
 WHERE id = $1
 LIMIT 1
 `
@@ -30,12 +101,16 @@ func (q *Queries) GetLessonByID(ctx context.Context, id uuid.UUID) (Lesson, erro
 		&i.EstimatedMinutes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.PublishedRevisionID,
+		&i.IsPreview,
 	)
 	return i, err
 }
 
 const getLessonBySlug = `-- name: GetLessonBySlug :one
-SELECT id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at FROM lessons
+SELECT id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at, status, publish_at, published_revision_id, is_preview FROM lessons
 WHERE module_id = $1 AND slug = $2
 LIMIT 1
 `
@@ -58,12 +133,16 @@ func (q *Queries) GetLessonBySlug(ctx context.Context, arg GetLessonBySlugParams
 		&i.EstimatedMinutes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.PublishedRevisionID,
+		&i.IsPreview,
 	)
 	return i, err
 }
 
 const getLessonsByModule = `-- name: GetLessonsByModule :many
-SELECT id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at FROM lessons
+SELECT id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at, status, publish_at, published_revision_id, is_preview FROM lessons
 WHERE module_id = $1
 ORDER BY order_index ASC
 `
@@ -87,6 +166,123 @@ func (q *Queries) GetLessonsByModule(ctx context.Context, moduleID uuid.UUID) ([
 			&i.EstimatedMinutes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.PublishAt,
+			&i.PublishedRevisionID,
+			&i.IsPreview,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishedLessonBySlug = `-- name: GetPublishedLessonBySlug :one
+SELECT
+    l.id,
+    l.module_id,
+    l.slug,
+    l.order_index,
+    l.estimated_minutes,
+    l.is_preview,
+    lr.title,
+    lr.content,
+    lr.id AS revision_id
+FROM lessons l
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+WHERE l.module_id = $1
+  AND l.slug = $2
+  AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+LIMIT 1
+`
+
+type GetPublishedLessonBySlugParams struct {
+	ModuleID uuid.UUID `json:"module_id"`
+	Slug     string    `json:"slug"`
+}
+
+type GetPublishedLessonBySlugRow struct {
+	ID               uuid.UUID `json:"id"`
+	ModuleID         uuid.UUID `json:"module_id"`
+	Slug             string    `json:"slug"`
+	OrderIndex       int32     `json:"order_index"`
+	EstimatedMinutes int32     `json:"estimated_minutes"`
+	IsPreview        bool      `json:"is_preview"`
+	Title            string    `json:"title"`
+	Content          string    `json:"content"`
+	RevisionID       uuid.UUID `json:"revision_id"`
+}
+
+func (q *Queries) GetPublishedLessonBySlug(ctx context.Context, arg GetPublishedLessonBySlugParams) (GetPublishedLessonBySlugRow, error) {
+	row := q.db.QueryRow(ctx, getPublishedLessonBySlug, arg.ModuleID, arg.Slug)
+	var i GetPublishedLessonBySlugRow
+	err := row.Scan(
+		&i.ID,
+		&i.ModuleID,
+		&i.Slug,
+		&i.OrderIndex,
+		&i.EstimatedMinutes,
+		&i.IsPreview,
+		&i.Title,
+		&i.Content,
+		&i.RevisionID,
+	)
+	return i, err
+}
+
+const getPublishedLessonsByModule = `-- name: GetPublishedLessonsByModule :many
+SELECT
+    l.id,
+    l.module_id,
+    l.slug,
+    l.order_index,
+    l.estimated_minutes,
+    l.is_preview,
+    lr.title,
+    lr.content,
+    lr.id AS revision_id
+FROM lessons l
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+WHERE l.module_id = $1
+  AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+ORDER BY l.order_index ASC
+`
+
+type GetPublishedLessonsByModuleRow struct {
+	ID               uuid.UUID `json:"id"`
+	ModuleID         uuid.UUID `json:"module_id"`
+	Slug             string    `json:"slug"`
+	OrderIndex       int32     `json:"order_index"`
+	EstimatedMinutes int32     `json:"estimated_minutes"`
+	IsPreview        bool      `json:"is_preview"`
+	Title            string    `json:"title"`
+	Content          string    `json:"content"`
+	RevisionID       uuid.UUID `json:"revision_id"`
+}
+
+func (q *Queries) GetPublishedLessonsByModule(ctx context.Context, moduleID uuid.UUID) ([]GetPublishedLessonsByModuleRow, error) {
+	rows, err := q.db.Query(ctx, getPublishedLessonsByModule, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPublishedLessonsByModuleRow{}
+	for rows.Next() {
+		var i GetPublishedLessonsByModuleRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ModuleID,
+			&i.Slug,
+			&i.OrderIndex,
+			&i.EstimatedMinutes,
+			&i.IsPreview,
+			&i.Title,
+			&i.Content,
+			&i.RevisionID,
 		); err != nil {
 			return nil, err
 		}
@@ -97,3 +293,230 @@ func (q *Queries) GetLessonsByModule(ctx context.Context, moduleID uuid.UUID) ([
 	}
 	return items, nil
 }
+
+const isLessonPublished = `-- name: IsLessonPublished :one
+SELECT EXISTS (
+    SELECT 1
+    FROM lessons l
+    JOIN modules m ON m.id = l.module_id
+    WHERE l.id = $1
+      AND l.published_revision_id IS NOT NULL
+      AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+      AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+)
+`
+
+func (q *Queries) IsLessonPublished(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isLessonPublished, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listPreviewLessons = `-- name: ListPreviewLessons :many
+SELECT
+    l.id,
+    l.module_id,
+    l.slug,
+    lr.title
+FROM lessons l
+JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+JOIN modules m ON m.id = l.module_id
+WHERE l.is_preview
+  AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+  AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+ORDER BY m.order_index ASC, l.order_index ASC
+`
+
+type ListPreviewLessonsRow struct {
+	ID       uuid.UUID `json:"id"`
+	ModuleID uuid.UUID `json:"module_id"`
+	Slug     string    `json:"slug"`
+	Title    string    `json:"title"`
+}
+
+// Published preview lessons of published modules, titled as published.
+func (q *Queries) ListPreviewLessons(ctx context.Context) ([]ListPreviewLessonsRow, error) {
+	rows, err := q.db.Query(ctx, listPreviewLessons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPreviewLessonsRow{}
+	for rows.Next() {
+		var i ListPreviewLessonsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ModuleID,
+			&i.Slug,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setLessonOrderIndex = `-- name: SetLessonOrderIndex :exec
+UPDATE lessons
+SET order_index = $2
+WHERE id = $1
+`
+
+type SetLessonOrderIndexParams struct {
+	ID         uuid.UUID `json:"id"`
+	OrderIndex int32     `json:"order_index"`
+}
+
+func (q *Queries) SetLessonOrderIndex(ctx context.Context, arg SetLessonOrderIndexParams) error {
+	_, err := q.db.Exec(ctx, setLessonOrderIndex, arg.ID, arg.OrderIndex)
+	return err
+}
+
+const setLessonStatus = `-- name: SetLessonStatus :one
+UPDATE lessons
+SET
+    status                = $1,
+    publish_at            = $2,
+    published_revision_id = COALESCE($3, published_revision_id)
+WHERE id = $4
+RETURNING id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at, status, publish_at, published_revision_id, is_preview
+`
+
+type SetLessonStatusParams struct {
+	Status              ContentStatus      `json:"status"`
+	PublishAt           pgtype.Timestamptz `json:"publish_at"`
+	PublishedRevisionID pgtype.UUID        `json:"published_revision_id"`
+	ID                  uuid.UUID          `json:"id"`
+}
+
+func (q *Queries) SetLessonStatus(ctx context.Context, arg SetLessonStatusParams) (Lesson, error) {
+	row := q.db.QueryRow(ctx, setLessonStatus,
+		arg.Status,
+		arg.PublishAt,
+		arg.PublishedRevisionID,
+		arg.ID,
+	)
+	var i Lesson
+	err := row.Scan(
+		&i.ID,
+		&i.ModuleID,
+		&i.Title,
+		&i.Slug,
+		&i.Content,
+		&i.OrderIndex,
+		&i.EstimatedMinutes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.PublishedRevisionID,
+		&i.IsPreview,
+	)
+	return i, err
+}
+
+const updateLesson = `-- name: UpdateLesson :one
+UPDATE lessons
+SET
+    title             = COALESCE($1, title),
+    slug              = COALESCE($2, slug),
+    content           = COALESCE($3, content),
+    estimated_minutes = COALESCE($4, estimated_minutes),
+    is_preview        = COALESCE($5, is_preview)
+WHERE id = $6
+RETURNING id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at, status, publish_at, published_revision_id, is_preview
+`
+
+type UpdateLessonParams struct {
+	Title            *string   `json:"title"`
+	Slug             *string   `json:"slug"`
+	Content          *string   `json:"content"`
+	EstimatedMinutes *int32    `json:"estimated_minutes"`
+	IsPreview        *bool     `json:"is_preview"`
+	ID               uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateLesson(ctx context.Context, arg UpdateLessonParams) (Lesson, error) {
+	row := q.db.QueryRow(ctx, updateLesson,
+		arg.Title,
+		arg.Slug,
+		arg.Content,
+		arg.EstimatedMinutes,
+		arg.IsPreview,
+		arg.ID,
+	)
+	var i Lesson
+	err := row.Scan(
+		&i.ID,
+		&i.ModuleID,
+		&i.Title,
+		&i.Slug,
+		&i.Content,
+		&i.OrderIndex,
+		&i.EstimatedMinutes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.PublishedRevisionID,
+		&i.IsPreview,
+	)
+	return i, err
+}
+
+const upsertLesson = `-- name: UpsertLesson :one
+INSERT INTO lessons (module_id, title, slug, content, estimated_minutes, is_preview, order_index)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (module_id, slug) DO UPDATE
+SET
+    title             = EXCLUDED.title,
+    content           = EXCLUDED.content,
+    estimated_minutes = EXCLUDED.estimated_minutes,
+    is_preview        = EXCLUDED.is_preview,
+    order_index       = EXCLUDED.order_index
+RETURNING id, module_id, title, slug, content, order_index, estimated_minutes, created_at, updated_at, status, publish_at, published_revision_id, is_preview
+`
+
+type UpsertLessonParams struct {
+	ModuleID         uuid.UUID `json:"module_id"`
+	Title            string    `json:"title"`
+	Slug             string    `json:"slug"`
+	Content          string    `json:"content"`
+	EstimatedMinutes int32     `json:"estimated_minutes"`
+	IsPreview        bool      `json:"is_preview"`
+	OrderIndex       int32     `json:"order_index"`
+}
+
+func (q *Queries) UpsertLesson(ctx context.Context, arg UpsertLessonParams) (Lesson, error) {
+	row := q.db.QueryRow(ctx, upsertLesson,
+		arg.ModuleID,
+		arg.Title,
+		arg.Slug,
+		arg.Content,
+		arg.EstimatedMinutes,
+		arg.IsPreview,
+		arg.OrderIndex,
+	)
+	var i Lesson
+	err := row.Scan(
+		&i.ID,
+		&i.ModuleID,
+		&i.Title,
+		&i.Slug,
+		&i.Content,
+		&i.OrderIndex,
+		&i.EstimatedMinutes,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.PublishedRevisionID,
+		&i.IsPreview,
+	)
+	return i, err
+}
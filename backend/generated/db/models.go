@@ -12,6 +12,70 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ContentStatus string
+
+const (
+	ContentStatusDraft     ContentStatus = "draft"
+	ContentStatusScheduled ContentStatus = "scheduled"
+	ContentStatusPublished ContentStatus = "published"
+	ContentStatusArchived  ContentStatus = "archived"
+)
+
+func (e *ContentStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ContentStatus(s)
+	case string:
+		*e = ContentStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ContentStatus: %T", src)
+	}
+	return nil
+}
+
+type NullContentStatus struct {
+	ContentStatus ContentStatus `json:"content_status"`
+	Valid         bool          `json:"valid"` // Valid is true if ContentStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullContentStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ContentStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ContentStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullContentStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ContentStatus), nil
+}
+
+func (e ContentStatus) Valid() bool {
+	switch e {
+	case ContentStatusDraft,
+		ContentStatusScheduled,
+		ContentStatusPublished,
+		ContentStatusArchived:
+		return true
+	}
+	return false
+}
+
+func AllContentStatusValues() []ContentStatus {
+	return []ContentStatus{
+		ContentStatusDraft,
+		ContentStatusScheduled,
+		ContentStatusPublished,
+		ContentStatusArchived,
+	}
+}
+
 type SubmissionStatus string
 
 const (
@@ -140,6 +204,67 @@ func AllSubscriptionStatusValues() []SubscriptionStatus {
 	}
 }
 
+type XpSource string
+
+const (
+	XpSourceLesson     XpSource = "lesson"
+	XpSourceSkill      XpSource = "skill"
+	XpSourceSubmission XpSource = "submission"
+)
+
+func (e *XpSource) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = XpSource(s)
+	case string:
+		*e = XpSource(s)
+	default:
+		return fmt.Errorf("unsupported scan type for XpSource: %T", src)
+	}
+	return nil
+}
+
+type NullXpSource struct {
+	XpSource XpSource `json:"xp_source"`
+	Valid    bool     `json:"valid"` // Valid is true if XpSource is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullXpSource) Scan(value interface{}) error {
+	if value == nil {
+		ns.XpSource, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.XpSource.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullXpSource) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.XpSource), nil
+}
+
+func (e XpSource) Valid() bool {
+	switch e {
+	case XpSourceLesson,
+		XpSourceSkill,
+		XpSourceSubmission:
+		return true
+	}
+	return false
+}
+
+func AllXpSourceValues() []XpSource {
+	return []XpSource{
+		XpSourceLesson,
+		XpSourceSkill,
+		XpSourceSubmission,
+	}
+}
+
 type Assignment struct {
 	ID             uuid.UUID          `json:"id"`
 	ModuleID       uuid.UUID          `json:"module_id"`
@@ -151,16 +276,82 @@ type Assignment struct {
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
+type Certificate struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	ModuleID      pgtype.UUID        `json:"module_id"`
+	RecipientName string             `json:"recipient_name"`
+	ModuleTitle   string             `json:"module_title"`
+	IssuedAt      pgtype.Timestamptz `json:"issued_at"`
+}
+
+type ImpersonationSession struct {
+	ID           uuid.UUID          `json:"id"`
+	AdminID      pgtype.UUID        `json:"admin_id"`
+	TargetUserID pgtype.UUID        `json:"target_user_id"`
+	Reason       string             `json:"reason"`
+	IpAddress    string             `json:"ip_address"`
+	UserAgent    string             `json:"user_agent"`
+	StartedAt    pgtype.Timestamptz `json:"started_at"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	EndedAt      pgtype.Timestamptz `json:"ended_at"`
+}
+
 type Lesson struct {
-	ID               uuid.UUID          `json:"id"`
-	ModuleID         uuid.UUID          `json:"module_id"`
-	Title            string             `json:"title"`
-	Slug             string             `json:"slug"`
-	Content          string             `json:"content"`
-	OrderIndex       int32              `json:"order_index"`
-	EstimatedMinutes int32              `json:"estimated_minutes"`
+	ID                  uuid.UUID          `json:"id"`
+	ModuleID            uuid.UUID          `json:"module_id"`
+	Title               string             `json:"title"`
+	Slug                string             `json:"slug"`
+	Content             string             `json:"content"`
+	OrderIndex          int32              `json:"order_index"`
+	EstimatedMinutes    int32              `json:"estimated_minutes"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+	Status              ContentStatus      `json:"status"`
+	PublishAt           pgtype.Timestamptz `json:"publish_at"`
+	PublishedRevisionID pgtype.UUID        `json:"published_revision_id"`
+	IsPreview           bool               `json:"is_preview"`
+}
+
+type LessonPrerequisite struct {
+	LessonID         uuid.UUID          `json:"lesson_id"`
+	RequiredLessonID uuid.UUID          `json:"required_lesson_id"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+type LessonProgressArchive struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	LessonID    uuid.UUID          `json:"lesson_id"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	ArchivedAt  pgtype.Timestamptz `json:"archived_at"`
+}
+
+type LessonRevision struct {
+	ID             uuid.UUID          `json:"id"`
+	LessonID       uuid.UUID          `json:"lesson_id"`
+	RevisionNumber int32              `json:"revision_number"`
+	Title          string             `json:"title"`
+	Content        string             `json:"content"`
+	AuthorID       pgtype.UUID        `json:"author_id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type LessonView struct {
+	UserID         uuid.UUID          `json:"user_id"`
+	LessonID       uuid.UUID          `json:"lesson_id"`
+	StartedAt      pgtype.Timestamptz `json:"started_at"`
+	LastViewedAt   pgtype.Timestamptz `json:"last_viewed_at"`
+	ScrollPosition float64            `json:"scroll_position"`
+	Section        *string            `json:"section"`
+}
+
+type MfaRecoveryCode struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type Module struct {
@@ -172,6 +363,65 @@ type Module struct {
 	EstimatedHours pgtype.Numeric     `json:"estimated_hours"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	Status         ContentStatus      `json:"status"`
+	PublishAt      pgtype.Timestamptz `json:"publish_at"`
+}
+
+type ModulePrerequisite struct {
+	ModuleID         uuid.UUID          `json:"module_id"`
+	RequiredModuleID uuid.UUID          `json:"required_module_id"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type PersonalAccessToken struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Name        string             `json:"name"`
+	TokenHash   string             `json:"token_hash"`
+	TokenPrefix string             `json:"token_prefix"`
+	Scopes      []string           `json:"scopes"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt  pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt   pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	FamilyID  uuid.UUID          `json:"family_id"`
+	TokenHash string             `json:"token_hash"`
+	UserAgent string             `json:"user_agent"`
+	IpAddress string             `json:"ip_address"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	RotatedAt pgtype.Timestamptz `json:"rotated_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Role struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type RolePermission struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
 }
 
 type Skill struct {
@@ -182,6 +432,14 @@ type Skill struct {
 	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
 
+type SkillProgressArchive struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	SkillID     uuid.UUID          `json:"skill_id"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	ArchivedAt  pgtype.Timestamptz `json:"archived_at"`
+}
+
 type Submission struct {
 	ID             uuid.UUID          `json:"id"`
 	AssignmentID   uuid.UUID          `json:"assignment_id"`
@@ -205,6 +463,22 @@ type User struct {
 	SubscriptionStatus   SubscriptionStatus `json:"subscription_status"`
 	CreatedAt            pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	EmailVerifiedAt      pgtype.Timestamptz `json:"email_verified_at"`
+	GithubID             *int64             `json:"github_id"`
+	GithubLogin          *string            `json:"github_login"`
+	TotpSecret           *string            `json:"totp_secret"`
+	TotpEnabledAt        pgtype.Timestamptz `json:"totp_enabled_at"`
+	TotpLastStep         *int64             `json:"totp_last_step"`
+	FailedLoginAttempts  int32              `json:"failed_login_attempts"`
+	LastFailedLoginAt    pgtype.Timestamptz `json:"last_failed_login_at"`
+	LockedUntil          pgtype.Timestamptz `json:"locked_until"`
+	Timezone             string             `json:"timezone"`
+}
+
+type UserBadge struct {
+	UserID   uuid.UUID          `json:"user_id"`
+	Badge    string             `json:"badge"`
+	EarnedAt pgtype.Timestamptz `json:"earned_at"`
 }
 
 type UserLessonProgress struct {
@@ -218,3 +492,12 @@ type UserSkillProgress struct {
 	SkillID     uuid.UUID          `json:"skill_id"`
 	CompletedAt pgtype.Timestamptz `json:"completed_at"`
 }
+
+type XpEvent struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Source    XpSource           `json:"source"`
+	SourceID  uuid.UUID          `json:"source_id"`
+	Xp        int32              `json:"xp"`
+	AwardedAt pgtype.Timestamptz `json:"awarded_at"`
+}
@@ -9,10 +9,90 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countModuleProgress = `-- name: CountModuleProgress :one
+SELECT
+    (SELECT COUNT(*) FROM user_lesson_progress ulp JOIN lessons l ON l.id = ulp.lesson_id WHERE l.module_id = $1) +
+    (SELECT COUNT(*) FROM lesson_progress_archive lpa JOIN lessons l ON l.id = lpa.lesson_id WHERE l.module_id = $1) +
+    (SELECT COUNT(*) FROM user_skill_progress usp JOIN skills s ON s.id = usp.skill_id WHERE s.module_id = $1) +
+    (SELECT COUNT(*) FROM skill_progress_archive spa JOIN skills s ON s.id = spa.skill_id WHERE s.module_id = $1)
+`
+
+// Lesson and skill completions in the module, now or before a module reset.
+func (q *Queries) CountModuleProgress(ctx context.Context, moduleID uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, countModuleProgress, moduleID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const countModuleSubmissions = `-- name: CountModuleSubmissions :one
+SELECT COUNT(*)
+FROM submissions s
+JOIN assignments a ON a.id = s.assignment_id
+WHERE a.module_id = $1
+`
+
+func (q *Queries) CountModuleSubmissions(ctx context.Context, moduleID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countModuleSubmissions, moduleID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createModule = `-- name: CreateModule :one
+INSERT INTO modules (title, slug, description, estimated_hours, order_index)
+VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(order_index), 0) + 1 FROM modules))
+RETURNING id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at
+`
+
+type CreateModuleParams struct {
+	Title          string         `json:"title"`
+	Slug           string         `json:"slug"`
+	Description    string         `json:"description"`
+	EstimatedHours pgtype.Numeric `json:"estimated_hours"`
+}
+
+func (q *Queries) CreateModule(ctx context.Context, arg CreateModuleParams) (Module, error) {
+	row := q.db.QueryRow(ctx, createModule,
+		arg.Title,
+		arg.Slug,
+		arg.Description,
+		arg.EstimatedHours,
+	)
+	var i Module
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.OrderIndex,
+		&i.EstimatedHours,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+	)
+	return i, err
+}
+
+const deleteModule = `-- name: DeleteModule :execrows
+DELETE FROM modules
+WHERE id = $1
+`
+
+func (q *Queries) DeleteModule(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteModule, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getModuleByID = `-- name: GetModuleByID :one
-SELECT id, title, slug, description, order_index, estimated_hours, created_at, updated_at FROM modules
+SELECT id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at FROM modules
 WHERE id = $1
 LIMIT 1
 `
@@ -29,12 +109,14 @@ func (q *Queries) GetModuleByID(ctx context.Context, id uuid.UUID) (Module, erro
 		&i.EstimatedHours,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
 	)
 	return i, err
 }
 
 const getModuleBySlug = `-- name: GetModuleBySlug :one
-SELECT id, title, slug, description, order_index, estimated_hours, created_at, updated_at FROM modules
+SELECT id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at FROM modules
 WHERE slug = $1
 LIMIT 1
 `
@@ -51,12 +133,39 @@ func (q *Queries) GetModuleBySlug(ctx context.Context, slug string) (Module, err
 		&i.EstimatedHours,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+	)
+	return i, err
+}
+
+const getPublishedModuleBySlug = `-- name: GetPublishedModuleBySlug :one
+SELECT id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at FROM modules
+WHERE slug = $1
+  AND (status = 'published' OR (status = 'scheduled' AND publish_at <= NOW()))
+LIMIT 1
+`
+
+func (q *Queries) GetPublishedModuleBySlug(ctx context.Context, slug string) (Module, error) {
+	row := q.db.QueryRow(ctx, getPublishedModuleBySlug, slug)
+	var i Module
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.OrderIndex,
+		&i.EstimatedHours,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
 	)
 	return i, err
 }
 
 const listModules = `-- name: ListModules :many
-SELECT id, title, slug, description, order_index, estimated_hours, created_at, updated_at FROM modules
+SELECT id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at FROM modules
 ORDER BY order_index ASC
 `
 
@@ -78,6 +187,45 @@ func (q *Queries) ListModules(ctx context.Context) ([]Module, error) {
 			&i.EstimatedHours,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.PublishAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublishedModules = `-- name: ListPublishedModules :many
+SELECT id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at FROM modules
+WHERE status = 'published' OR (status = 'scheduled' AND publish_at <= NOW())
+ORDER BY order_index ASC
+`
+
+func (q *Queries) ListPublishedModules(ctx context.Context) ([]Module, error) {
+	rows, err := q.db.Query(ctx, listPublishedModules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Module{}
+	for rows.Next() {
+		var i Module
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.OrderIndex,
+			&i.EstimatedHours,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.PublishAt,
 		); err != nil {
 			return nil, err
 		}
@@ -88,3 +236,139 @@ func (q *Queries) ListModules(ctx context.Context) ([]Module, error) {
 	}
 	return items, nil
 }
+
+const setModuleOrderIndex = `-- name: SetModuleOrderIndex :exec
+UPDATE modules
+SET order_index = $2
+WHERE id = $1
+`
+
+type SetModuleOrderIndexParams struct {
+	ID         uuid.UUID `json:"id"`
+	OrderIndex int32     `json:"order_index"`
+}
+
+func (q *Queries) SetModuleOrderIndex(ctx context.Context, arg SetModuleOrderIndexParams) error {
+	_, err := q.db.Exec(ctx, setModuleOrderIndex, arg.ID, arg.OrderIndex)
+	return err
+}
+
+const setModuleStatus = `-- name: SetModuleStatus :one
+UPDATE modules
+SET
+    status     = $2,
+    publish_at = $3
+WHERE id = $1
+RETURNING id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at
+`
+
+type SetModuleStatusParams struct {
+	ID        uuid.UUID          `json:"id"`
+	Status    ContentStatus      `json:"status"`
+	PublishAt pgtype.Timestamptz `json:"publish_at"`
+}
+
+func (q *Queries) SetModuleStatus(ctx context.Context, arg SetModuleStatusParams) (Module, error) {
+	row := q.db.QueryRow(ctx, setModuleStatus, arg.ID, arg.Status, arg.PublishAt)
+	var i Module
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.OrderIndex,
+		&i.EstimatedHours,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+	)
+	return i, err
+}
+
+const updateModule = `-- name: UpdateModule :one
+UPDATE modules
+SET
+    title           = COALESCE($1, title),
+    slug            = COALESCE($2, slug),
+    description     = COALESCE($3, description),
+    estimated_hours = COALESCE($4, estimated_hours)
+WHERE id = $5
+RETURNING id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at
+`
+
+type UpdateModuleParams struct {
+	Title          *string        `json:"title"`
+	Slug           *string        `json:"slug"`
+	Description    *string        `json:"description"`
+	EstimatedHours pgtype.Numeric `json:"estimated_hours"`
+	ID             uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) UpdateModule(ctx context.Context, arg UpdateModuleParams) (Module, error) {
+	row := q.db.QueryRow(ctx, updateModule,
+		arg.Title,
+		arg.Slug,
+		arg.Description,
+		arg.EstimatedHours,
+		arg.ID,
+	)
+	var i Module
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.OrderIndex,
+		&i.EstimatedHours,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+	)
+	return i, err
+}
+
+const upsertModule = `-- name: UpsertModule :one
+INSERT INTO modules (title, slug, description, estimated_hours, order_index)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (slug) DO UPDATE
+SET
+    title           = EXCLUDED.title,
+    description     = EXCLUDED.description,
+    estimated_hours = EXCLUDED.estimated_hours,
+    order_index     = EXCLUDED.order_index
+RETURNING id, title, slug, description, order_index, estimated_hours, created_at, updated_at, status, publish_at
+`
+
+type UpsertModuleParams struct {
+	Title          string         `json:"title"`
+	Slug           string         `json:"slug"`
+	Description    string         `json:"description"`
+	EstimatedHours pgtype.Numeric `json:"estimated_hours"`
+	OrderIndex     int32          `json:"order_index"`
+}
+
+func (q *Queries) UpsertModule(ctx context.Context, arg UpsertModuleParams) (Module, error) {
+	row := q.db.QueryRow(ctx, upsertModule,
+		arg.Title,
+		arg.Slug,
+		arg.Description,
+		arg.EstimatedHours,
+		arg.OrderIndex,
+	)
+	var i Module
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Description,
+		&i.OrderIndex,
+		&i.EstimatedHours,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.PublishAt,
+	)
+	return i, err
+}
@@ -0,0 +1,376 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: prerequisites.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const addLessonPrerequisite = `-- name: AddLessonPrerequisite :exec
+INSERT INTO lesson_prerequisites (lesson_id, required_lesson_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddLessonPrerequisiteParams struct {
+	LessonID         uuid.UUID `json:"lesson_id"`
+	RequiredLessonID uuid.UUID `json:"required_lesson_id"`
+}
+
+func (q *Queries) AddLessonPrerequisite(ctx context.Context, arg AddLessonPrerequisiteParams) error {
+	_, err := q.db.Exec(ctx, addLessonPrerequisite, arg.LessonID, arg.RequiredLessonID)
+	return err
+}
+
+const addModulePrerequisite = `-- name: AddModulePrerequisite :exec
+INSERT INTO module_prerequisites (module_id, required_module_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddModulePrerequisiteParams struct {
+	ModuleID         uuid.UUID `json:"module_id"`
+	RequiredModuleID uuid.UUID `json:"required_module_id"`
+}
+
+func (q *Queries) AddModulePrerequisite(ctx context.Context, arg AddModulePrerequisiteParams) error {
+	_, err := q.db.Exec(ctx, addModulePrerequisite, arg.ModuleID, arg.RequiredModuleID)
+	return err
+}
+
+const deleteLessonPrerequisites = `-- name: DeleteLessonPrerequisites :exec
+DELETE FROM lesson_prerequisites
+WHERE lesson_id = $1
+`
+
+func (q *Queries) DeleteLessonPrerequisites(ctx context.Context, lessonID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteLessonPrerequisites, lessonID)
+	return err
+}
+
+const deleteModulePrerequisites = `-- name: DeleteModulePrerequisites :exec
+DELETE FROM module_prerequisites
+WHERE module_id = $1
+`
+
+func (q *Queries) DeleteModulePrerequisites(ctx context.Context, moduleID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteModulePrerequisites, moduleID)
+	return err
+}
+
+const getUnmetLessonPrerequisites = `-- name: GetUnmetLessonPrerequisites :many
+SELECT
+    rl.id    AS required_lesson_id,
+    rl.slug  AS required_lesson_slug,
+    lr.title AS required_lesson_title,
+    m.slug   AS module_slug
+FROM lesson_prerequisites lp
+JOIN lessons rl ON rl.id = lp.required_lesson_id
+JOIN lesson_revisions lr ON lr.id = rl.published_revision_id
+JOIN modules m ON m.id = rl.module_id
+WHERE lp.lesson_id = $1
+  AND (rl.status = 'published' OR (rl.status = 'scheduled' AND rl.publish_at <= NOW()))
+  AND NOT EXISTS (
+      SELECT 1 FROM user_lesson_progress ulp
+      WHERE ulp.user_id = $2 AND ulp.lesson_id = rl.id
+  )
+ORDER BY m.order_index ASC, rl.order_index ASC
+`
+
+type GetUnmetLessonPrerequisitesParams struct {
+	LessonID uuid.UUID `json:"lesson_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+type GetUnmetLessonPrerequisitesRow struct {
+	RequiredLessonID    uuid.UUID `json:"required_lesson_id"`
+	RequiredLessonSlug  string    `json:"required_lesson_slug"`
+	RequiredLessonTitle string    `json:"required_lesson_title"`
+	ModuleSlug          string    `json:"module_slug"`
+}
+
+func (q *Queries) GetUnmetLessonPrerequisites(ctx context.Context, arg GetUnmetLessonPrerequisitesParams) ([]GetUnmetLessonPrerequisitesRow, error) {
+	rows, err := q.db.Query(ctx, getUnmetLessonPrerequisites, arg.LessonID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUnmetLessonPrerequisitesRow{}
+	for rows.Next() {
+		var i GetUnmetLessonPrerequisitesRow
+		if err := rows.Scan(
+			&i.RequiredLessonID,
+			&i.RequiredLessonSlug,
+			&i.RequiredLessonTitle,
+			&i.ModuleSlug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnmetModulePrerequisites = `-- name: GetUnmetModulePrerequisites :many
+SELECT
+    rm.id    AS required_module_id,
+    rm.slug  AS required_module_slug,
+    rm.title AS required_module_title
+FROM module_prerequisites mp
+JOIN modules rm ON rm.id = mp.required_module_id
+WHERE mp.module_id = $1
+  AND (rm.status = 'published' OR (rm.status = 'scheduled' AND rm.publish_at <= NOW()))
+  AND EXISTS (
+      SELECT 1
+      FROM lessons l
+      WHERE l.module_id = rm.id
+        AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+        AND NOT EXISTS (
+            SELECT 1 FROM user_lesson_progress ulp
+            WHERE ulp.user_id = $2 AND ulp.lesson_id = l.id
+        )
+  )
+ORDER BY rm.order_index ASC
+`
+
+type GetUnmetModulePrerequisitesParams struct {
+	ModuleID uuid.UUID `json:"module_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+type GetUnmetModulePrerequisitesRow struct {
+	RequiredModuleID    uuid.UUID `json:"required_module_id"`
+	RequiredModuleSlug  string    `json:"required_module_slug"`
+	RequiredModuleTitle string    `json:"required_module_title"`
+}
+
+func (q *Queries) GetUnmetModulePrerequisites(ctx context.Context, arg GetUnmetModulePrerequisitesParams) ([]GetUnmetModulePrerequisitesRow, error) {
+	rows, err := q.db.Query(ctx, getUnmetModulePrerequisites, arg.ModuleID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUnmetModulePrerequisitesRow{}
+	for rows.Next() {
+		var i GetUnmetModulePrerequisitesRow
+		if err := rows.Scan(&i.RequiredModuleID, &i.RequiredModuleSlug, &i.RequiredModuleTitle); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLessonPrerequisiteEdges = `-- name: ListLessonPrerequisiteEdges :many
+SELECT lesson_id, required_lesson_id FROM lesson_prerequisites
+`
+
+type ListLessonPrerequisiteEdgesRow struct {
+	LessonID         uuid.UUID `json:"lesson_id"`
+	RequiredLessonID uuid.UUID `json:"required_lesson_id"`
+}
+
+func (q *Queries) ListLessonPrerequisiteEdges(ctx context.Context) ([]ListLessonPrerequisiteEdgesRow, error) {
+	rows, err := q.db.Query(ctx, listLessonPrerequisiteEdges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLessonPrerequisiteEdgesRow{}
+	for rows.Next() {
+		var i ListLessonPrerequisiteEdgesRow
+		if err := rows.Scan(&i.LessonID, &i.RequiredLessonID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLessonPrerequisites = `-- name: ListLessonPrerequisites :many
+SELECT
+    l.id,
+    l.module_id,
+    l.title,
+    l.slug,
+    m.slug AS module_slug
+FROM lesson_prerequisites lp
+JOIN lessons l ON l.id = lp.required_lesson_id
+JOIN modules m ON m.id = l.module_id
+WHERE lp.lesson_id = $1
+ORDER BY m.order_index ASC, l.order_index ASC
+`
+
+type ListLessonPrerequisitesRow struct {
+	ID         uuid.UUID `json:"id"`
+	ModuleID   uuid.UUID `json:"module_id"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	ModuleSlug string    `json:"module_slug"`
+}
+
+func (q *Queries) ListLessonPrerequisites(ctx context.Context, lessonID uuid.UUID) ([]ListLessonPrerequisitesRow, error) {
+	rows, err := q.db.Query(ctx, listLessonPrerequisites, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListLessonPrerequisitesRow{}
+	for rows.Next() {
+		var i ListLessonPrerequisitesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ModuleID,
+			&i.Title,
+			&i.Slug,
+			&i.ModuleSlug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listModulePrerequisiteEdges = `-- name: ListModulePrerequisiteEdges :many
+SELECT module_id, required_module_id FROM module_prerequisites
+`
+
+type ListModulePrerequisiteEdgesRow struct {
+	ModuleID         uuid.UUID `json:"module_id"`
+	RequiredModuleID uuid.UUID `json:"required_module_id"`
+}
+
+func (q *Queries) ListModulePrerequisiteEdges(ctx context.Context) ([]ListModulePrerequisiteEdgesRow, error) {
+	rows, err := q.db.Query(ctx, listModulePrerequisiteEdges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListModulePrerequisiteEdgesRow{}
+	for rows.Next() {
+		var i ListModulePrerequisiteEdgesRow
+		if err := rows.Scan(&i.ModuleID, &i.RequiredModuleID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listModulePrerequisites = `-- name: ListModulePrerequisites :many
+SELECT m.id, m.title, m.slug, m.description, m.order_index, m.estimated_hours, m.created_at, m.updated_at, m.status, m.publish_at
+FROM module_prerequisites mp
+JOIN modules m ON m.id = mp.required_module_id
+WHERE mp.module_id = $1
+ORDER BY m.order_index ASC
+`
+
+func (q *Queries) ListModulePrerequisites(ctx context.Context, moduleID uuid.UUID) ([]Module, error) {
+	rows, err := q.db.Query(ctx, listModulePrerequisites, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Module{}
+	for rows.Next() {
+		var i Module
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Description,
+			&i.OrderIndex,
+			&i.EstimatedHours,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.PublishAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnmetModulePrerequisites = `-- name: ListUnmetModulePrerequisites :many
+
+SELECT
+    mp.module_id,
+    rm.id    AS required_module_id,
+    rm.slug  AS required_module_slug,
+    rm.title AS required_module_title
+FROM module_prerequisites mp
+JOIN modules rm ON rm.id = mp.required_module_id
+WHERE (rm.status = 'published' OR (rm.status = 'scheduled' AND rm.publish_at <= NOW()))
+  AND EXISTS (
+      SELECT 1
+      FROM lessons l
+      WHERE l.module_id = rm.id
+        AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+        AND NOT EXISTS (
+            SELECT 1 FROM user_lesson_progress ulp
+            WHERE ulp.user_id = $1 AND ulp.lesson_id = l.id
+        )
+  )
+ORDER BY rm.order_index ASC
+`
+
+type ListUnmetModulePrerequisitesRow struct {
+	ModuleID            uuid.UUID `json:"module_id"`
+	RequiredModuleID    uuid.UUID `json:"required_module_id"`
+	RequiredModuleSlug  string    `json:"required_module_slug"`
+	RequiredModuleTitle string    `json:"required_module_title"`
+}
+
+// A module counts as completed once the user has completed every one of its
+// published lessons. Prerequisites that learners can't see (drafts, archived
+// content) never block anyone.
+func (q *Queries) ListUnmetModulePrerequisites(ctx context.Context, userID uuid.UUID) ([]ListUnmetModulePrerequisitesRow, error) {
+	rows, err := q.db.Query(ctx, listUnmetModulePrerequisites, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnmetModulePrerequisitesRow{}
+	for rows.Next() {
+		var i ListUnmetModulePrerequisitesRow
+		if err := rows.Scan(
+			&i.ModuleID,
+			&i.RequiredModuleID,
+			&i.RequiredModuleSlug,
+			&i.RequiredModuleTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
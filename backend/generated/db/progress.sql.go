@@ -9,8 +9,102 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const exportUserArchivedLessonProgress = `-- name: ExportUserArchivedLessonProgress :many
+SELECT
+    m.slug AS module_slug,
+    l.slug AS lesson_slug,
+    l.title AS lesson_title,
+    lpa.completed_at,
+    lpa.archived_at
+FROM lesson_progress_archive lpa
+JOIN lessons l ON l.id = lpa.lesson_id
+JOIN modules m ON m.id = l.module_id
+WHERE lpa.user_id = $1
+ORDER BY lpa.archived_at ASC, lpa.completed_at ASC
+`
+
+type ExportUserArchivedLessonProgressRow struct {
+	ModuleSlug  string             `json:"module_slug"`
+	LessonSlug  string             `json:"lesson_slug"`
+	LessonTitle string             `json:"lesson_title"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	ArchivedAt  pgtype.Timestamptz `json:"archived_at"`
+}
+
+func (q *Queries) ExportUserArchivedLessonProgress(ctx context.Context, userID uuid.UUID) ([]ExportUserArchivedLessonProgressRow, error) {
+	rows, err := q.db.Query(ctx, exportUserArchivedLessonProgress, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportUserArchivedLessonProgressRow{}
+	for rows.Next() {
+		var i ExportUserArchivedLessonProgressRow
+		if err := rows.Scan(
+			&i.ModuleSlug,
+			&i.LessonSlug,
+			&i.LessonTitle,
+			&i.CompletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportUserLessonProgress = `-- name: ExportUserLessonProgress :many
+SELECT
+    m.slug AS module_slug,
+    l.slug AS lesson_slug,
+    l.title AS lesson_title,
+    ulp.completed_at
+FROM user_lesson_progress ulp
+JOIN lessons l ON l.id = ulp.lesson_id
+JOIN modules m ON m.id = l.module_id
+WHERE ulp.user_id = $1
+ORDER BY ulp.completed_at ASC
+`
+
+type ExportUserLessonProgressRow struct {
+	ModuleSlug  string             `json:"module_slug"`
+	LessonSlug  string             `json:"lesson_slug"`
+	LessonTitle string             `json:"lesson_title"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+}
+
+func (q *Queries) ExportUserLessonProgress(ctx context.Context, userID uuid.UUID) ([]ExportUserLessonProgressRow, error) {
+	rows, err := q.db.Query(ctx, exportUserLessonProgress, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportUserLessonProgressRow{}
+	for rows.Next() {
+		var i ExportUserLessonProgressRow
+		if err := rows.Scan(
+			&i.ModuleSlug,
+			&i.LessonSlug,
+			&i.LessonTitle,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getCompletedLessonCountByModule = `-- name: GetCompletedLessonCountByModule :one
 SELECT COUNT(ulp.lesson_id)
 FROM user_lesson_progress ulp
@@ -55,6 +149,107 @@ func (q *Queries) GetCompletedLessonIDs(ctx context.Context, userID uuid.UUID) (
 	return items, nil
 }
 
+const listModuleProgress = `-- name: ListModuleProgress :many
+WITH lesson_counts AS (
+    SELECT
+        l.module_id,
+        COUNT(*)             AS total,
+        COUNT(ulp.lesson_id) AS completed
+    FROM lessons l
+    LEFT JOIN user_lesson_progress ulp ON ulp.lesson_id = l.id AND ulp.user_id = $1
+    WHERE l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW())
+    GROUP BY l.module_id
+),
+skill_counts AS (
+    SELECT
+        s.module_id,
+        COUNT(*)            AS total,
+        COUNT(usp.skill_id) AS completed
+    FROM skills s
+    LEFT JOIN user_skill_progress usp ON usp.skill_id = s.id AND usp.user_id = $1
+    GROUP BY s.module_id
+),
+next_lessons AS (
+    SELECT DISTINCT ON (l.module_id)
+        l.module_id,
+        l.id,
+        l.slug,
+        lr.title
+    FROM lessons l
+    JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+    WHERE (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+      AND NOT EXISTS (
+          SELECT 1 FROM user_lesson_progress ulp
+          WHERE ulp.user_id = $1 AND ulp.lesson_id = l.id
+      )
+    ORDER BY l.module_id, l.order_index ASC
+)
+SELECT
+    m.id                           AS module_id,
+    COALESCE(lc.total, 0)::int     AS total_lessons,
+    COALESCE(lc.completed, 0)::int AS completed_lessons,
+    COALESCE(sc.total, 0)::int     AS total_skills,
+    COALESCE(sc.completed, 0)::int AS completed_skills,
+    (a.id IS NOT NULL)::bool       AS has_assignment,
+    sub.status                     AS submission_status,
+    nl.id                          AS next_lesson_id,
+    nl.slug                        AS next_lesson_slug,
+    nl.title                       AS next_lesson_title
+FROM modules m
+LEFT JOIN lesson_counts lc ON lc.module_id = m.id
+LEFT JOIN skill_counts sc ON sc.module_id = m.id
+LEFT JOIN assignments a ON a.module_id = m.id
+LEFT JOIN submissions sub ON sub.assignment_id = a.id AND sub.user_id = $1
+LEFT JOIN next_lessons nl ON nl.module_id = m.id
+`
+
+type ListModuleProgressRow struct {
+	ModuleID         uuid.UUID            `json:"module_id"`
+	TotalLessons     int32                `json:"total_lessons"`
+	CompletedLessons int32                `json:"completed_lessons"`
+	TotalSkills      int32                `json:"total_skills"`
+	CompletedSkills  int32                `json:"completed_skills"`
+	HasAssignment    bool                 `json:"has_assignment"`
+	SubmissionStatus NullSubmissionStatus `json:"submission_status"`
+	NextLessonID     pgtype.UUID          `json:"next_lesson_id"`
+	NextLessonSlug   *string              `json:"next_lesson_slug"`
+	NextLessonTitle  *string              `json:"next_lesson_title"`
+}
+
+// One row per module with the user's progress through its published lessons,
+// its skills and its assignment, plus the first published lesson they haven't
+// completed yet.
+func (q *Queries) ListModuleProgress(ctx context.Context, userID uuid.UUID) ([]ListModuleProgressRow, error) {
+	rows, err := q.db.Query(ctx, listModuleProgress, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListModuleProgressRow{}
+	for rows.Next() {
+		var i ListModuleProgressRow
+		if err := rows.Scan(
+			&i.ModuleID,
+			&i.TotalLessons,
+			&i.CompletedLessons,
+			&i.TotalSkills,
+			&i.CompletedSkills,
+			&i.HasAssignment,
+			&i.SubmissionStatus,
+			&i.NextLessonID,
+			&i.NextLessonSlug,
+			&i.NextLessonTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markLessonComplete = `-- name: MarkLessonComplete :exec
 INSERT INTO user_lesson_progress (user_id, lesson_id)
 VALUES ($1, $2)
@@ -70,3 +265,49 @@ func (q *Queries) MarkLessonComplete(ctx context.Context, arg MarkLessonComplete
 	_, err := q.db.Exec(ctx, markLessonComplete, arg.UserID, arg.LessonID)
 	return err
 }
+
+const resetModuleLessonProgress = `-- name: ResetModuleLessonProgress :execrows
+WITH d AS (
+    DELETE FROM user_lesson_progress ulp
+    USING lessons l
+    WHERE l.id = ulp.lesson_id
+      AND ulp.user_id = $1
+      AND l.module_id = $2
+    RETURNING ulp.user_id, ulp.lesson_id, ulp.completed_at
+)
+INSERT INTO lesson_progress_archive (user_id, lesson_id, completed_at)
+SELECT user_id, lesson_id, completed_at FROM d
+`
+
+type ResetModuleLessonProgressParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	ModuleID uuid.UUID `json:"module_id"`
+}
+
+// Archives exactly the rows it deletes, so a lesson completed concurrently is
+// either both archived and deleted or left alone.
+func (q *Queries) ResetModuleLessonProgress(ctx context.Context, arg ResetModuleLessonProgressParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resetModuleLessonProgress, arg.UserID, arg.ModuleID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const unmarkLessonComplete = `-- name: UnmarkLessonComplete :execrows
+DELETE FROM user_lesson_progress
+WHERE user_id = $1 AND lesson_id = $2
+`
+
+type UnmarkLessonCompleteParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	LessonID uuid.UUID `json:"lesson_id"`
+}
+
+func (q *Queries) UnmarkLessonComplete(ctx context.Context, arg UnmarkLessonCompleteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unmarkLessonComplete, arg.UserID, arg.LessonID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
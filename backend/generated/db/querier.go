@@ -11,34 +11,177 @@ import (
 )
 
 type Querier interface {
+	AddLessonPrerequisite(ctx context.Context, arg AddLessonPrerequisiteParams) error
+	AddModulePrerequisite(ctx context.Context, arg AddModulePrerequisiteParams) error
+	AddUserBadge(ctx context.Context, arg AddUserBadgeParams) (int64, error)
+	AwardXP(ctx context.Context, arg AwardXPParams) (int64, error)
+	ChangeUserEmail(ctx context.Context, arg ChangeUserEmailParams) (User, error)
+	// Learners who completed the lesson, now or before a module reset.
+	CountLessonProgress(ctx context.Context, lessonID uuid.UUID) (int32, error)
+	// Lesson and skill completions in the module, now or before a module reset.
+	CountModuleProgress(ctx context.Context, moduleID uuid.UUID) (int32, error)
+	CountModuleSubmissions(ctx context.Context, moduleID uuid.UUID) (int64, error)
+	// Learners who completed the skill, now or before a module reset.
+	CountSkillProgress(ctx context.Context, skillID uuid.UUID) (int32, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateGitHubUser(ctx context.Context, arg CreateGitHubUserParams) (User, error)
+	CreateImpersonationSession(ctx context.Context, arg CreateImpersonationSessionParams) (ImpersonationSession, error)
+	CreateLesson(ctx context.Context, arg CreateLessonParams) (Lesson, error)
+	CreateLessonRevision(ctx context.Context, arg CreateLessonRevisionParams) (LessonRevision, error)
+	CreateModule(ctx context.Context, arg CreateModuleParams) (Module, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateSkill(ctx context.Context, arg CreateSkillParams) (Skill, error)
 	CreateSubmission(ctx context.Context, arg CreateSubmissionParams) (Submission, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteAssignmentByModuleID(ctx context.Context, moduleID uuid.UUID) (int64, error)
+	DeleteLesson(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteLessonPrerequisites(ctx context.Context, lessonID uuid.UUID) error
+	DeleteModule(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteModulePrerequisites(ctx context.Context, moduleID uuid.UUID) error
+	DeleteSkill(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	DeleteUserRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	DisableUserTOTP(ctx context.Context, id uuid.UUID) error
+	EnableUserTOTP(ctx context.Context, id uuid.UUID) (int64, error)
+	EndImpersonationSession(ctx context.Context, id uuid.UUID) (int64, error)
+	ExportUserArchivedLessonProgress(ctx context.Context, userID uuid.UUID) ([]ExportUserArchivedLessonProgressRow, error)
+	ExportUserArchivedSkillProgress(ctx context.Context, userID uuid.UUID) ([]ExportUserArchivedSkillProgressRow, error)
+	ExportUserLessonProgress(ctx context.Context, userID uuid.UUID) ([]ExportUserLessonProgressRow, error)
+	ExportUserLessonViews(ctx context.Context, userID uuid.UUID) ([]ExportUserLessonViewsRow, error)
+	ExportUserSkillProgress(ctx context.Context, userID uuid.UUID) ([]ExportUserSkillProgressRow, error)
+	ExportUserSubmissions(ctx context.Context, userID uuid.UUID) ([]ExportUserSubmissionsRow, error)
+	ExportUserXPEvents(ctx context.Context, userID uuid.UUID) ([]ExportUserXPEventsRow, error)
+	GetActivePersonalAccessToken(ctx context.Context, tokenHash string) (GetActivePersonalAccessTokenRow, error)
 	GetAssignmentByID(ctx context.Context, id uuid.UUID) (Assignment, error)
 	GetAssignmentByModuleID(ctx context.Context, moduleID uuid.UUID) (Assignment, error)
+	// The counters badge rules are evaluated against. A module counts as finished
+	// once every one of its published lessons is completed.
+	GetBadgeStats(ctx context.Context, userID uuid.UUID) (GetBadgeStatsRow, error)
+	GetCertificate(ctx context.Context, id uuid.UUID) (Certificate, error)
 	GetCompletedLessonCountByModule(ctx context.Context, arg GetCompletedLessonCountByModuleParams) (int64, error)
 	GetCompletedLessonIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 	GetCompletedSkillIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	GetLatestLessonRevision(ctx context.Context, lessonID uuid.UUID) (LessonRevision, error)
 	GetLessonByID(ctx context.Context, id uuid.UUID) (Lesson, error)
 	GetLessonBySlug(ctx context.Context, arg GetLessonBySlugParams) (Lesson, error)
+	GetLessonRevision(ctx context.Context, arg GetLessonRevisionParams) (LessonRevision, error)
+	GetLessonRevisionByID(ctx context.Context, id uuid.UUID) (LessonRevision, error)
 	GetLessonsByModule(ctx context.Context, moduleID uuid.UUID) ([]Lesson, error)
 	GetModuleByID(ctx context.Context, id uuid.UUID) (Module, error)
 	GetModuleBySlug(ctx context.Context, slug string) (Module, error)
+	// The first published lesson, in curriculum order, that the user hasn't
+	// completed, skipping the lesson they're already resuming.
+	GetNextRecommendedLesson(ctx context.Context, arg GetNextRecommendedLessonParams) (GetNextRecommendedLessonRow, error)
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	GetPublishedLessonBySlug(ctx context.Context, arg GetPublishedLessonBySlugParams) (GetPublishedLessonBySlugRow, error)
+	GetPublishedLessonsByModule(ctx context.Context, moduleID uuid.UUID) ([]GetPublishedLessonsByModuleRow, error)
+	GetPublishedModuleBySlug(ctx context.Context, slug string) (Module, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	// The most recently viewed published lesson the user hasn't completed.
+	GetResumeLesson(ctx context.Context, userID uuid.UUID) (GetResumeLessonRow, error)
 	GetSkillByID(ctx context.Context, id uuid.UUID) (Skill, error)
 	GetSkillsByModule(ctx context.Context, moduleID uuid.UUID) ([]Skill, error)
 	GetSubmissionByAssignmentAndUser(ctx context.Context, arg GetSubmissionByAssignmentAndUserParams) (Submission, error)
 	GetSubmissionByID(ctx context.Context, id uuid.UUID) (Submission, error)
 	GetSubmissionsByUser(ctx context.Context, userID uuid.UUID) ([]Submission, error)
+	GetUnmetLessonPrerequisites(ctx context.Context, arg GetUnmetLessonPrerequisitesParams) ([]GetUnmetLessonPrerequisitesRow, error)
+	GetUnmetModulePrerequisites(ctx context.Context, arg GetUnmetModulePrerequisitesParams) ([]GetUnmetModulePrerequisitesRow, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByGitHubID(ctx context.Context, githubID *int64) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserByStripeCustomerID(ctx context.Context, stripeCustomerID *string) (User, error)
 	GetUserSubscriptionStatus(ctx context.Context, id uuid.UUID) (SubscriptionStatus, error)
+	GetUserXP(ctx context.Context, userID uuid.UUID) (int32, error)
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID) error
+	IsImpersonationSessionActive(ctx context.Context, id uuid.UUID) (bool, error)
+	// Activity is bucketed into days by Postgres, so a time zone has to be in its
+	// tz database as well as Go's.
+	IsKnownTimezone(ctx context.Context, name string) (bool, error)
+	IsLessonPublished(ctx context.Context, id uuid.UUID) (bool, error)
+	// Issuing again returns the existing certificate unchanged.
+	IssueCertificate(ctx context.Context, arg IssueCertificateParams) (Certificate, error)
+	LinkUserGitHub(ctx context.Context, arg LinkUserGitHubParams) (User, error)
+	// Per-day activity counts over the user's whole history, with days taken in
+	// the given time zone. Completions cleared by a module reset still count.
+	ListActivityByDay(ctx context.Context, arg ListActivityByDayParams) ([]ListActivityByDayRow, error)
+	ListImpersonationSessions(ctx context.Context, arg ListImpersonationSessionsParams) ([]ImpersonationSession, error)
+	ListLessonPrerequisiteEdges(ctx context.Context) ([]ListLessonPrerequisiteEdgesRow, error)
+	ListLessonPrerequisites(ctx context.Context, lessonID uuid.UUID) ([]ListLessonPrerequisitesRow, error)
+	ListLessonRevisions(ctx context.Context, lessonID uuid.UUID) ([]ListLessonRevisionsRow, error)
+	ListModulePrerequisiteEdges(ctx context.Context) ([]ListModulePrerequisiteEdgesRow, error)
+	ListModulePrerequisites(ctx context.Context, moduleID uuid.UUID) ([]Module, error)
+	// One row per module with the user's progress through its published lessons,
+	// its skills and its assignment, plus the first published lesson they haven't
+	// completed yet.
+	ListModuleProgress(ctx context.Context, userID uuid.UUID) ([]ListModuleProgressRow, error)
 	ListModules(ctx context.Context) ([]Module, error)
 	ListPendingSubmissions(ctx context.Context) ([]Submission, error)
+	ListPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]PersonalAccessToken, error)
+	// Published preview lessons of published modules, titled as published.
+	ListPreviewLessons(ctx context.Context) ([]ListPreviewLessonsRow, error)
+	ListPublishedModules(ctx context.Context) ([]Module, error)
+	ListRoles(ctx context.Context) ([]ListRolesRow, error)
+	// A module counts as completed once the user has completed every one of its
+	// published lessons. Prerequisites that learners can't see (drafts, archived
+	// content) never block anyone.
+	ListUnmetModulePrerequisites(ctx context.Context, userID uuid.UUID) ([]ListUnmetModulePrerequisitesRow, error)
+	ListUserBadges(ctx context.Context, userID uuid.UUID) ([]ListUserBadgesRow, error)
+	ListUserCertificates(ctx context.Context, userID uuid.UUID) ([]Certificate, error)
+	LockUser(ctx context.Context, arg LockUserParams) error
 	MarkLessonComplete(ctx context.Context, arg MarkLessonCompleteParams) error
 	MarkSkillComplete(ctx context.Context, arg MarkSkillCompleteParams) error
+	MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error)
+	// Failures older than window_start no longer count: the counter starts over.
+	RecordFailedLogin(ctx context.Context, arg RecordFailedLoginParams) (int32, error)
+	// Position fields left NULL keep their stored value, so serving a lesson only
+	// bumps last_viewed_at.
+	RecordLessonView(ctx context.Context, arg RecordLessonViewParams) (LessonView, error)
+	RecordTOTPStep(ctx context.Context, arg RecordTOTPStepParams) (int64, error)
+	ResetFailedLogins(ctx context.Context, id uuid.UUID) error
+	// Archives exactly the rows it deletes, so a lesson completed concurrently is
+	// either both archived and deleted or left alone.
+	ResetModuleLessonProgress(ctx context.Context, arg ResetModuleLessonProgressParams) (int64, error)
+	// Like ResetModuleLessonProgress, archives exactly the rows it deletes.
+	ResetModuleSkillProgress(ctx context.Context, arg ResetModuleSkillProgressParams) (int64, error)
 	ReviewSubmission(ctx context.Context, arg ReviewSubmissionParams) (Submission, error)
+	RevokeOtherRefreshTokens(ctx context.Context, arg RevokeOtherRefreshTokensParams) error
+	RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (int64, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	RevokeXP(ctx context.Context, arg RevokeXPParams) (int64, error)
+	RotateRefreshToken(ctx context.Context, id uuid.UUID) (int64, error)
+	// Searches published modules, lessons and skills, best match first. Lesson
+	// excerpts are only built for preview lessons or when include_excerpts is set.
+	SearchContent(ctx context.Context, arg SearchContentParams) ([]SearchContentRow, error)
+	SetLessonOrderIndex(ctx context.Context, arg SetLessonOrderIndexParams) error
+	SetLessonStatus(ctx context.Context, arg SetLessonStatusParams) (Lesson, error)
+	SetModuleOrderIndex(ctx context.Context, arg SetModuleOrderIndexParams) error
+	SetModuleStatus(ctx context.Context, arg SetModuleStatusParams) (Module, error)
+	SetSkillOrderIndex(ctx context.Context, arg SetSkillOrderIndexParams) error
+	SetUserTOTPSecret(ctx context.Context, arg SetUserTOTPSecretParams) error
+	TouchPersonalAccessToken(ctx context.Context, id uuid.UUID) error
+	UnmarkLessonComplete(ctx context.Context, arg UnmarkLessonCompleteParams) (int64, error)
+	UnmarkSkillComplete(ctx context.Context, arg UnmarkSkillCompleteParams) (int64, error)
+	UpdateLesson(ctx context.Context, arg UpdateLessonParams) (Lesson, error)
+	UpdateModule(ctx context.Context, arg UpdateModuleParams) (Module, error)
+	UpdateSkill(ctx context.Context, arg UpdateSkillParams) (Skill, error)
+	UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
 	UpdateUserStripeCustomerID(ctx context.Context, arg UpdateUserStripeCustomerIDParams) (User, error)
 	UpdateUserSubscription(ctx context.Context, arg UpdateUserSubscriptionParams) (User, error)
+	UpdateUserTimezone(ctx context.Context, arg UpdateUserTimezoneParams) (User, error)
+	UpsertAssignment(ctx context.Context, arg UpsertAssignmentParams) (Assignment, error)
+	UpsertLesson(ctx context.Context, arg UpsertLessonParams) (Lesson, error)
+	UpsertModule(ctx context.Context, arg UpsertModuleParams) (Module, error)
+	UsePasswordResetToken(ctx context.Context, id uuid.UUID) (int64, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	// Goes through users.role rather than the role in the caller's token, so a
+	// role change takes effect on the next request.
+	UserHasPermission(ctx context.Context, arg UserHasPermissionParams) (bool, error)
 }
 
 var _ Querier = (*Queries)(nil)
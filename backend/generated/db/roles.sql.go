@@ -0,0 +1,71 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: roles.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const listRoles = `-- name: ListRoles :many
+SELECT
+    r.name,
+    r.description,
+    COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')::TEXT[] AS permissions
+FROM roles r
+LEFT JOIN role_permissions rp ON rp.role = r.name
+GROUP BY r.name, r.description
+ORDER BY r.name
+`
+
+type ListRolesRow struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+func (q *Queries) ListRoles(ctx context.Context) ([]ListRolesRow, error) {
+	rows, err := q.db.Query(ctx, listRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRolesRow{}
+	for rows.Next() {
+		var i ListRolesRow
+		if err := rows.Scan(&i.Name, &i.Description, &i.Permissions); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const userHasPermission = `-- name: UserHasPermission :one
+SELECT EXISTS (
+    SELECT 1 FROM users u
+    JOIN role_permissions rp ON rp.role = u.role
+    WHERE u.id = $1 AND rp.permission = $2
+)
+`
+
+type UserHasPermissionParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Permission string    `json:"permission"`
+}
+
+// Goes through users.role rather than the role in the caller's token, so a
+// role change takes effect on the next request.
+func (q *Queries) UserHasPermission(ctx context.Context, arg UserHasPermissionParams) (bool, error) {
+	row := q.db.QueryRow(ctx, userHasPermission, arg.UserID, arg.Permission)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
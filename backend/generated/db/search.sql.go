@@ -0,0 +1,125 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: search.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const searchContent = `-- name: SearchContent :many
+WITH search AS (
+    SELECT websearch_to_tsquery('english', $6::text) AS q
+)
+SELECT hits.kind, hits.id, hits.module_slug, hits.lesson_slug, hits.title, hits.snippet, hits.rank
+FROM (
+    SELECT
+        'module'::text AS kind,
+        m.id,
+        m.slug AS module_slug,
+        ''::text AS lesson_slug,
+        ts_headline('english', m.title, search.q, $1::text)::text AS title,
+        ts_headline('english', m.description, search.q, $2::text)::text AS snippet,
+        ts_rank(module_search_vector(m.title, m.description), search.q) AS rank
+    FROM modules m, search
+    WHERE module_search_vector(m.title, m.description) @@ search.q
+      AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+
+    UNION ALL
+
+    SELECT
+        'lesson'::text,
+        l.id,
+        m.slug,
+        l.slug,
+        ts_headline('english', lr.title, search.q, $1::text),
+        CASE WHEN $3::boolean OR l.is_preview
+            THEN ts_headline('english', lr.content, search.q, $2::text)
+            ELSE ''
+        END,
+        ts_rank(lesson_search_vector(lr.title, lr.content), search.q)
+    FROM lessons l
+    JOIN lesson_revisions lr ON lr.id = l.published_revision_id
+    JOIN modules m ON m.id = l.module_id, search
+    WHERE lesson_search_vector(lr.title, lr.content) @@ search.q
+      AND (l.status = 'published' OR (l.status = 'scheduled' AND l.publish_at <= NOW()))
+      AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+
+    UNION ALL
+
+    SELECT
+        'skill'::text,
+        s.id,
+        m.slug,
+        ''::text,
+        ts_headline('english', s.skill_name, search.q, $1::text),
+        ''::text,
+        ts_rank(skill_search_vector(s.skill_name), search.q)
+    FROM skills s
+    JOIN modules m ON m.id = s.module_id, search
+    WHERE skill_search_vector(s.skill_name) @@ search.q
+      AND (m.status = 'published' OR (m.status = 'scheduled' AND m.publish_at <= NOW()))
+) hits
+ORDER BY hits.rank DESC, hits.title ASC
+LIMIT $5 OFFSET $4
+`
+
+type SearchContentParams struct {
+	TitleOptions    string `json:"title_options"`
+	HeadlineOptions string `json:"headline_options"`
+	IncludeExcerpts bool   `json:"include_excerpts"`
+	PageOffset      int32  `json:"page_offset"`
+	PageLimit       int32  `json:"page_limit"`
+	Query           string `json:"query"`
+}
+
+type SearchContentRow struct {
+	Kind       string    `json:"kind"`
+	ID         uuid.UUID `json:"id"`
+	ModuleSlug string    `json:"module_slug"`
+	LessonSlug string    `json:"lesson_slug"`
+	Title      string    `json:"title"`
+	Snippet    string    `json:"snippet"`
+	Rank       float32   `json:"rank"`
+}
+
+// Searches published modules, lessons and skills, best match first. Lesson
+// excerpts are only built for preview lessons or when include_excerpts is set.
+func (q *Queries) SearchContent(ctx context.Context, arg SearchContentParams) ([]SearchContentRow, error) {
+	rows, err := q.db.Query(ctx, searchContent,
+		arg.TitleOptions,
+		arg.HeadlineOptions,
+		arg.IncludeExcerpts,
+		arg.PageOffset,
+		arg.PageLimit,
+		arg.Query,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchContentRow{}
+	for rows.Next() {
+		var i SearchContentRow
+		if err := rows.Scan(
+			&i.Kind,
+			&i.ID,
+			&i.ModuleSlug,
+			&i.LessonSlug,
+			&i.Title,
+			&i.Snippet,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
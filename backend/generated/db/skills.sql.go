@@ -9,8 +9,143 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countSkillProgress = `-- name: CountSkillProgress :one
+SELECT
+    (SELECT COUNT(*) FROM user_skill_progress usp WHERE usp.skill_id = $1) +
+    (SELECT COUNT(*) FROM skill_progress_archive spa WHERE spa.skill_id = $1)
+`
+
+// Learners who completed the skill, now or before a module reset.
+func (q *Queries) CountSkillProgress(ctx context.Context, skillID uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, countSkillProgress, skillID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createSkill = `-- name: CreateSkill :one
+INSERT INTO skills (module_id, skill_name, order_index)
+VALUES ($1, $2, (SELECT COALESCE(MAX(order_index), 0) + 1 FROM skills WHERE module_id = $1))
+RETURNING id, module_id, skill_name, order_index, created_at
+`
+
+type CreateSkillParams struct {
+	ModuleID  uuid.UUID `json:"module_id"`
+	SkillName string    `json:"skill_name"`
+}
+
+func (q *Queries) CreateSkill(ctx context.Context, arg CreateSkillParams) (Skill, error) {
+	row := q.db.QueryRow(ctx, createSkill, arg.ModuleID, arg.SkillName)
+	var i Skill
+	err := row.Scan(
+		&i.ID,
+		&i.ModuleID,
+		&i.SkillName,
+		&i.OrderIndex,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteSkill = `-- name: DeleteSkill :execrows
+DELETE FROM skills
+WHERE id = $1
+`
+
+func (q *Queries) DeleteSkill(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSkill, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const exportUserArchivedSkillProgress = `-- name: ExportUserArchivedSkillProgress :many
+SELECT
+    m.slug AS module_slug,
+    s.skill_name,
+    spa.completed_at,
+    spa.archived_at
+FROM skill_progress_archive spa
+JOIN skills s ON s.id = spa.skill_id
+JOIN modules m ON m.id = s.module_id
+WHERE spa.user_id = $1
+ORDER BY spa.archived_at ASC, spa.completed_at ASC
+`
+
+type ExportUserArchivedSkillProgressRow struct {
+	ModuleSlug  string             `json:"module_slug"`
+	SkillName   string             `json:"skill_name"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	ArchivedAt  pgtype.Timestamptz `json:"archived_at"`
+}
+
+func (q *Queries) ExportUserArchivedSkillProgress(ctx context.Context, userID uuid.UUID) ([]ExportUserArchivedSkillProgressRow, error) {
+	rows, err := q.db.Query(ctx, exportUserArchivedSkillProgress, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportUserArchivedSkillProgressRow{}
+	for rows.Next() {
+		var i ExportUserArchivedSkillProgressRow
+		if err := rows.Scan(
+			&i.ModuleSlug,
+			&i.SkillName,
+			&i.CompletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportUserSkillProgress = `-- name: ExportUserSkillProgress :many
+SELECT
+    m.slug AS module_slug,
+    s.skill_name,
+    usp.completed_at
+FROM user_skill_progress usp
+JOIN skills s ON s.id = usp.skill_id
+JOIN modules m ON m.id = s.module_id
+WHERE usp.user_id = $1
+ORDER BY usp.completed_at ASC
+`
+
+type ExportUserSkillProgressRow struct {
+	ModuleSlug  string             `json:"module_slug"`
+	SkillName   string             `json:"skill_name"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+}
+
+func (q *Queries) ExportUserSkillProgress(ctx context.Context, userID uuid.UUID) ([]ExportUserSkillProgressRow, error) {
+	rows, err := q.db.Query(ctx, exportUserSkillProgress, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportUserSkillProgressRow{}
+	for rows.Next() {
+		var i ExportUserSkillProgressRow
+		if err := rows.Scan(&i.ModuleSlug, &i.SkillName, &i.CompletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getCompletedSkillIDs = `-- name: GetCompletedSkillIDs :many
 SELECT skill_id FROM user_skill_progress
 WHERE user_id = $1
@@ -102,3 +237,89 @@ func (q *Queries) MarkSkillComplete(ctx context.Context, arg MarkSkillCompletePa
 	_, err := q.db.Exec(ctx, markSkillComplete, arg.UserID, arg.SkillID)
 	return err
 }
+
+const resetModuleSkillProgress = `-- name: ResetModuleSkillProgress :execrows
+WITH d AS (
+    DELETE FROM user_skill_progress usp
+    USING skills s
+    WHERE s.id = usp.skill_id
+      AND usp.user_id = $1
+      AND s.module_id = $2
+    RETURNING usp.user_id, usp.skill_id, usp.completed_at
+)
+INSERT INTO skill_progress_archive (user_id, skill_id, completed_at)
+SELECT user_id, skill_id, completed_at FROM d
+`
+
+type ResetModuleSkillProgressParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	ModuleID uuid.UUID `json:"module_id"`
+}
+
+// Like ResetModuleLessonProgress, archives exactly the rows it deletes.
+func (q *Queries) ResetModuleSkillProgress(ctx context.Context, arg ResetModuleSkillProgressParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resetModuleSkillProgress, arg.UserID, arg.ModuleID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setSkillOrderIndex = `-- name: SetSkillOrderIndex :exec
+UPDATE skills
+SET order_index = $2
+WHERE id = $1
+`
+
+type SetSkillOrderIndexParams struct {
+	ID         uuid.UUID `json:"id"`
+	OrderIndex int32     `json:"order_index"`
+}
+
+func (q *Queries) SetSkillOrderIndex(ctx context.Context, arg SetSkillOrderIndexParams) error {
+	_, err := q.db.Exec(ctx, setSkillOrderIndex, arg.ID, arg.OrderIndex)
+	return err
+}
+
+const unmarkSkillComplete = `-- name: UnmarkSkillComplete :execrows
+DELETE FROM user_skill_progress
+WHERE user_id = $1 AND skill_id = $2
+`
+
+type UnmarkSkillCompleteParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	SkillID uuid.UUID `json:"skill_id"`
+}
+
+func (q *Queries) UnmarkSkillComplete(ctx context.Context, arg UnmarkSkillCompleteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unmarkSkillComplete, arg.UserID, arg.SkillID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateSkill = `-- name: UpdateSkill :one
+UPDATE skills
+SET skill_name = $2
+WHERE id = $1
+RETURNING id, module_id, skill_name, order_index, created_at
+`
+
+type UpdateSkillParams struct {
+	ID        uuid.UUID `json:"id"`
+	SkillName string    `json:"skill_name"`
+}
+
+func (q *Queries) UpdateSkill(ctx context.Context, arg UpdateSkillParams) (Skill, error) {
+	row := q.db.QueryRow(ctx, updateSkill, arg.ID, arg.SkillName)
+	var i Skill
+	err := row.Scan(
+		&i.ID,
+		&i.ModuleID,
+		&i.SkillName,
+		&i.OrderIndex,
+		&i.CreatedAt,
+	)
+	return i, err
+}
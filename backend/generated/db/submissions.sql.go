@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createSubmission = `-- name: CreateSubmission :one
@@ -46,6 +47,63 @@ func (q *Queries) CreateSubmission(ctx context.Context, arg CreateSubmissionPara
 	return i, err
 }
 
+const exportUserSubmissions = `-- name: ExportUserSubmissions :many
+SELECT
+    m.slug AS module_slug,
+    a.title AS assignment_title,
+    s.github_url,
+    s.written_answers,
+    s.status,
+    s.feedback,
+    s.submitted_at,
+    s.reviewed_at
+FROM submissions s
+JOIN assignments a ON a.id = s.assignment_id
+JOIN modules m ON m.id = a.module_id
+WHERE s.user_id = $1
+ORDER BY s.submitted_at ASC
+`
+
+type ExportUserSubmissionsRow struct {
+	ModuleSlug      string             `json:"module_slug"`
+	AssignmentTitle string             `json:"assignment_title"`
+	GithubUrl       string             `json:"github_url"`
+	WrittenAnswers  string             `json:"written_answers"`
+	Status          SubmissionStatus   `json:"status"`
+	Feedback        *string            `json:"feedback"`
+	SubmittedAt     pgtype.Timestamptz `json:"submitted_at"`
+	ReviewedAt      pgtype.Timestamptz `json:"reviewed_at"`
+}
+
+func (q *Queries) ExportUserSubmissions(ctx context.Context, userID uuid.UUID) ([]ExportUserSubmissionsRow, error) {
+	rows, err := q.db.Query(ctx, exportUserSubmissions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportUserSubmissionsRow{}
+	for rows.Next() {
+		var i ExportUserSubmissionsRow
+		if err := rows.Scan(
+			&i.ModuleSlug,
+			&i.AssignmentTitle,
+			&i.GithubUrl,
+			&i.WrittenAnswers,
+			&i.Status,
+			&i.Feedback,
+			&i.SubmittedAt,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSubmissionByAssignmentAndUser = `-- name: GetSubmissionByAssignmentAndUser :one
 SELECT id, assignment_id, user_id, github_url, written_answers, status, feedback, submitted_at, reviewed_at FROM submissions
 WHERE assignment_id = $1 AND user_id = $2
@@ -9,12 +9,102 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const changeUserEmail = `-- name: ChangeUserEmail :one
+UPDATE users
+SET
+    email             = $1,
+    email_verified_at = NOW()
+WHERE id = $2 AND email = $3
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
+`
+
+type ChangeUserEmailParams struct {
+	NewEmail     string    `json:"new_email"`
+	ID           uuid.UUID `json:"id"`
+	CurrentEmail string    `json:"current_email"`
+}
+
+func (q *Queries) ChangeUserEmail(ctx context.Context, arg ChangeUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, changeUserEmail, arg.NewEmail, arg.ID, arg.CurrentEmail)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Name,
+		&i.Role,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.SubscriptionStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const createGitHubUser = `-- name: CreateGitHubUser :one
+INSERT INTO users (email, password_hash, name, github_id, github_login, email_verified_at)
+VALUES ($1, '', $2, $3, $4, NOW())
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
+`
+
+type CreateGitHubUserParams struct {
+	Email       string  `json:"email"`
+	Name        string  `json:"name"`
+	GithubID    *int64  `json:"github_id"`
+	GithubLogin *string `json:"github_login"`
+}
+
+func (q *Queries) CreateGitHubUser(ctx context.Context, arg CreateGitHubUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, createGitHubUser,
+		arg.Email,
+		arg.Name,
+		arg.GithubID,
+		arg.GithubLogin,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Name,
+		&i.Role,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.SubscriptionStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (email, password_hash, name)
 VALUES ($1, $2, $3)
-RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
 `
 
 type CreateUserParams struct {
@@ -37,12 +127,32 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.SubscriptionStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
 	)
 	return i, err
 }
 
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUser, id)
+	return err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at FROM users
+SELECT id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone FROM users
 WHERE email = $1
 LIMIT 1
 `
@@ -61,12 +171,56 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.SubscriptionStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const getUserByGitHubID = `-- name: GetUserByGitHubID :one
+SELECT id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone FROM users
+WHERE github_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetUserByGitHubID(ctx context.Context, githubID *int64) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByGitHubID, githubID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Name,
+		&i.Role,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.SubscriptionStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at FROM users
+SELECT id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone FROM users
 WHERE id = $1
 LIMIT 1
 `
@@ -85,12 +239,22 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.SubscriptionStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const getUserByStripeCustomerID = `-- name: GetUserByStripeCustomerID :one
-SELECT id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at FROM users
+SELECT id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone FROM users
 WHERE stripe_customer_id = $1
 LIMIT 1
 `
@@ -109,6 +273,16 @@ func (q *Queries) GetUserByStripeCustomerID(ctx context.Context, stripeCustomerI
 		&i.SubscriptionStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
 	)
 	return i, err
 }
@@ -126,11 +300,238 @@ func (q *Queries) GetUserSubscriptionStatus(ctx context.Context, id uuid.UUID) (
 	return subscription_status, err
 }
 
+const isKnownTimezone = `-- name: IsKnownTimezone :one
+SELECT EXISTS (
+    SELECT 1 FROM pg_catalog.pg_timezone_names WHERE name = $1::text
+)
+`
+
+// Activity is bucketed into days by Postgres, so a time zone has to be in its
+// tz database as well as Go's.
+func (q *Queries) IsKnownTimezone(ctx context.Context, name string) (bool, error) {
+	row := q.db.QueryRow(ctx, isKnownTimezone, name)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const linkUserGitHub = `-- name: LinkUserGitHub :one
+UPDATE users
+SET
+    github_id    = $2,
+    github_login = $3
+WHERE id = $1
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
+`
+
+type LinkUserGitHubParams struct {
+	ID          uuid.UUID `json:"id"`
+	GithubID    *int64    `json:"github_id"`
+	GithubLogin *string   `json:"github_login"`
+}
+
+func (q *Queries) LinkUserGitHub(ctx context.Context, arg LinkUserGitHubParams) (User, error) {
+	row := q.db.QueryRow(ctx, linkUserGitHub, arg.ID, arg.GithubID, arg.GithubLogin)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Name,
+		&i.Role,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.SubscriptionStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const lockUser = `-- name: LockUser :exec
+UPDATE users
+SET locked_until = $2
+WHERE id = $1
+`
+
+type LockUserParams struct {
+	ID          uuid.UUID          `json:"id"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) error {
+	_, err := q.db.Exec(ctx, lockUser, arg.ID, arg.LockedUntil)
+	return err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :execrows
+UPDATE users
+SET email_verified_at = NOW()
+WHERE id = $1 AND email = $2 AND email_verified_at IS NULL
+`
+
+type MarkUserEmailVerifiedParams struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markUserEmailVerified, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordFailedLogin = `-- name: RecordFailedLogin :one
+UPDATE users
+SET
+    failed_login_attempts = CASE
+        WHEN last_failed_login_at IS NULL OR last_failed_login_at < $1 THEN 1
+        ELSE failed_login_attempts + 1
+    END,
+    last_failed_login_at  = NOW()
+WHERE id = $2
+RETURNING failed_login_attempts
+`
+
+type RecordFailedLoginParams struct {
+	WindowStart pgtype.Timestamptz `json:"window_start"`
+	ID          uuid.UUID          `json:"id"`
+}
+
+// Failures older than window_start no longer count: the counter starts over.
+func (q *Queries) RecordFailedLogin(ctx context.Context, arg RecordFailedLoginParams) (int32, error) {
+	row := q.db.QueryRow(ctx, recordFailedLogin, arg.WindowStart, arg.ID)
+	var failed_login_attempts int32
+	err := row.Scan(&failed_login_attempts)
+	return failed_login_attempts, err
+}
+
+const resetFailedLogins = `-- name: ResetFailedLogins :exec
+UPDATE users
+SET
+    failed_login_attempts = 0,
+    locked_until          = NULL
+WHERE id = $1
+`
+
+func (q *Queries) ResetFailedLogins(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, resetFailedLogins, id)
+	return err
+}
+
+const updateUserName = `-- name: UpdateUserName :one
+UPDATE users
+SET name = $2
+WHERE id = $1
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
+`
+
+type UpdateUserNameParams struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func (q *Queries) UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserName, arg.ID, arg.Name)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Name,
+		&i.Role,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.SubscriptionStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           uuid.UUID `json:"id"`
+	PasswordHash string    `json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE id = $1
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
+`
+
+type UpdateUserRoleParams struct {
+	ID   uuid.UUID `json:"id"`
+	Role string    `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Name,
+		&i.Role,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.SubscriptionStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
+	)
+	return i, err
+}
+
 const updateUserStripeCustomerID = `-- name: UpdateUserStripeCustomerID :one
 UPDATE users
 SET stripe_customer_id = $2
 WHERE id = $1
-RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
 `
 
 type UpdateUserStripeCustomerIDParams struct {
@@ -152,6 +553,16 @@ func (q *Queries) UpdateUserStripeCustomerID(ctx context.Context, arg UpdateUser
 		&i.SubscriptionStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
 	)
 	return i, err
 }
@@ -162,7 +573,7 @@ SET
     stripe_subscription_id = $2,
     subscription_status    = $3
 WHERE stripe_customer_id = $1
-RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
 `
 
 type UpdateUserSubscriptionParams struct {
@@ -185,6 +596,56 @@ func (q *Queries) UpdateUserSubscription(ctx context.Context, arg UpdateUserSubs
 		&i.SubscriptionStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const updateUserTimezone = `-- name: UpdateUserTimezone :one
+UPDATE users
+SET timezone = $2
+WHERE id = $1
+RETURNING id, email, password_hash, name, role, stripe_customer_id, stripe_subscription_id, subscription_status, created_at, updated_at, email_verified_at, github_id, github_login, totp_secret, totp_enabled_at, totp_last_step, failed_login_attempts, last_failed_login_at, locked_until, timezone
+`
+
+type UpdateUserTimezoneParams struct {
+	ID       uuid.UUID `json:"id"`
+	Timezone string    `json:"timezone"`
+}
+
+func (q *Queries) UpdateUserTimezone(ctx context.Context, arg UpdateUserTimezoneParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserTimezone, arg.ID, arg.Timezone)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Name,
+		&i.Role,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.SubscriptionStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.GithubID,
+		&i.GithubLogin,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.FailedLoginAttempts,
+		&i.LastFailedLoginAt,
+		&i.LockedUntil,
+		&i.Timezone,
 	)
 	return i, err
 }
@@ -0,0 +1,106 @@
+// Package activity turns a learner's completions and submissions into a daily
+// calendar and streak statistics. Days are calendar dates in the learner's
+// time zone, represented as midnight UTC so they can be compared and stepped
+// with AddDate.
+package activity
+
+import (
+	"errors"
+	"time"
+
+	// Bundle the time zone database so LoadLocation works in minimal images.
+	_ "time/tzdata"
+)
+
+// DateLayout is the format of dates in requests and responses.
+const DateLayout = "2006-01-02"
+
+var errInvalidTimezone = errors.New("invalid time zone")
+
+// LoadLocation loads an IANA time zone such as "Europe/Berlin". Unlike
+// time.LoadLocation it rejects "" and "Local", which would depend on the
+// server's configuration.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errInvalidTimezone
+	}
+	return loc, nil
+}
+
+// Date returns the calendar day of t in its own location.
+func Date(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// ParseDate parses a YYYY-MM-DD date.
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse(DateLayout, s)
+}
+
+// Counts is the activity on one day.
+type Counts struct {
+	Lessons     int `json:"lessons"`
+	Skills      int `json:"skills"`
+	Submissions int `json:"submissions"`
+}
+
+func (c Counts) Total() int {
+	return c.Lessons + c.Skills + c.Submissions
+}
+
+// Day is one cell of the activity calendar.
+type Day struct {
+	Date string `json:"date"`
+	Counts
+	Total int `json:"total"`
+}
+
+// Calendar returns one entry per day from from to to inclusive, with zero
+// counts for days without activity.
+func Calendar(byDay map[time.Time]Counts, from, to time.Time) []Day {
+	days := []Day{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		c := byDay[d]
+		days = append(days, Day{Date: d.Format(DateLayout), Counts: c, Total: c.Total()})
+	}
+	return days
+}
+
+// Streaks are runs of consecutive active days.
+type Streaks struct {
+	Current    int    `json:"current"`
+	Longest    int    `json:"longest"`
+	LastActive string `json:"last_active,omitempty"`
+}
+
+// ComputeStreaks computes streaks from the active days in ascending order.
+// The current streak is still alive if its last day is yesterday: the learner
+// has until the end of today to extend it.
+func ComputeStreaks(active []time.Time, today time.Time) Streaks {
+	var s Streaks
+	if len(active) == 0 {
+		return s
+	}
+
+	run := 0
+	for i, d := range active {
+		if i > 0 && d.Equal(active[i-1].AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+		s.Longest = max(s.Longest, run)
+	}
+
+	last := active[len(active)-1]
+	s.LastActive = last.Format(DateLayout)
+	if last.Equal(today) || last.Equal(today.AddDate(0, 0, -1)) {
+		s.Current = run
+	}
+	return s
+}
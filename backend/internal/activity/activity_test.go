@@ -0,0 +1,106 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func days(t *testing.T, dates ...string) []time.Time {
+	t.Helper()
+	out := make([]time.Time, len(dates))
+	for i, s := range dates {
+		d, err := ParseDate(s)
+		if err != nil {
+			t.Fatalf("ParseDate(%q): %v", s, err)
+		}
+		out[i] = d
+	}
+	return out
+}
+
+func TestComputeStreaks(t *testing.T) {
+	today, _ := ParseDate("2025-03-10")
+
+	tests := []struct {
+		name   string
+		active []string
+		want   Streaks
+	}{
+		{name: "no activity", want: Streaks{}},
+		{
+			name:   "ends today",
+			active: []string{"2025-03-08", "2025-03-09", "2025-03-10"},
+			want:   Streaks{Current: 3, Longest: 3, LastActive: "2025-03-10"},
+		},
+		{
+			name:   "ends yesterday",
+			active: []string{"2025-03-08", "2025-03-09"},
+			want:   Streaks{Current: 2, Longest: 2, LastActive: "2025-03-09"},
+		},
+		{
+			name:   "ended two days ago",
+			active: []string{"2025-03-07", "2025-03-08"},
+			want:   Streaks{Current: 0, Longest: 2, LastActive: "2025-03-08"},
+		},
+		{
+			name:   "longest run is earlier",
+			active: []string{"2025-03-01", "2025-03-02", "2025-03-03", "2025-03-04", "2025-03-09", "2025-03-10"},
+			want:   Streaks{Current: 2, Longest: 4, LastActive: "2025-03-10"},
+		},
+		{
+			name:   "across a month boundary",
+			active: []string{"2025-02-27", "2025-02-28", "2025-03-01"},
+			want:   Streaks{Current: 0, Longest: 3, LastActive: "2025-03-01"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeStreaks(days(t, tt.active...), today); got != tt.want {
+				t.Errorf("ComputeStreaks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	d := days(t, "2025-03-01", "2025-03-02", "2025-03-03")
+	got := Calendar(map[time.Time]Counts{d[1]: {Lessons: 2, Skills: 1}}, d[0], d[2])
+
+	want := []Day{
+		{Date: "2025-03-01"},
+		{Date: "2025-03-02", Counts: Counts{Lessons: 2, Skills: 1}, Total: 3},
+		{Date: "2025-03-03"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Calendar() returned %d days, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("day %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if empty := Calendar(nil, d[2], d[0]); len(empty) != 0 {
+		t.Errorf("Calendar() with from after to = %v, want no days", empty)
+	}
+}
+
+func TestDate(t *testing.T) {
+	loc, err := LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 03:00 UTC on the 10th is still the evening of the 9th in Los Angeles.
+	at := time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC).In(loc)
+	if got := Date(at).Format(DateLayout); got != "2025-03-09" {
+		t.Errorf("Date() = %s, want 2025-03-09", got)
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	for _, name := range []string{"", "Local", "Nowhere/Special"} {
+		if _, err := LoadLocation(name); err == nil {
+			t.Errorf("LoadLocation(%q) succeeded, want an error", name)
+		}
+	}
+}
@@ -6,8 +6,14 @@ import (
 )
 
 const (
-	TokenTypeAccess  = "access"
-	TokenTypeRefresh = "refresh"
+	TokenTypeAccess      = "access"
+	TokenTypeRefresh     = "refresh"
+	TokenTypeEmailVerify = "email_verify"
+	TokenTypeOAuthState  = "oauth_state"
+	TokenTypeMFA         = "mfa_challenge"
+	TokenTypeEmailChange = "email_change"
+
+	TokenTypeEmailChangeApproval = "email_change_approval"
 )
 
 type Claims struct {
@@ -15,4 +21,16 @@ type Claims struct {
 	UserID    uuid.UUID `json:"uid"`
 	Role      string    `json:"role"`
 	TokenType string    `json:"typ"`
+	SessionID uuid.UUID `json:"sid,omitzero"`
+	Email     string    `json:"email,omitempty"`
+
+	// NewEmail is the address an email change token switches the account to.
+	NewEmail string `json:"new_email,omitempty"`
+
+	// MFA is set on session tokens minted after a second factor was checked.
+	MFA bool `json:"mfa,omitempty"`
+
+	// ImpersonatorID is the admin acting as UserID on impersonation tokens.
+	// SessionID is then the impersonation session, not a refresh token family.
+	ImpersonatorID uuid.UUID `json:"imp,omitzero"`
 }
@@ -12,61 +12,202 @@ import (
 
 type Service struct {
 	cfg *config.Config
+
+	// keys is nil when only the legacy HS256 JWT_SECRET is configured.
+	keys *KeySet
 }
 
-func NewService(cfg *config.Config) *Service {
-	return &Service{cfg: cfg}
+// NewService loads the asymmetric signing keys from JWT_KEY_DIR. JWT_SECRET is
+// only used to sign when no key directory is set, and otherwise keeps HS256
+// tokens issued before the switch verifiable until they expire.
+func NewService(cfg *config.Config) (*Service, error) {
+	s := &Service{cfg: cfg}
+
+	if cfg.JWTKeyDir != "" {
+		keys, err := LoadKeySet(cfg.JWTKeyDir, cfg.JWTActiveKeyID)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt keys: %w", err)
+		}
+		s.keys = keys
+	} else if cfg.JWTSecret == "" {
+		return nil, fmt.Errorf("either JWT_KEY_DIR or JWT_SECRET must be set")
+	}
+
+	return s, nil
 }
 
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	// RefreshTokenID and RefreshExpiresAt describe the refresh token so the
+	// caller can persist it in the refresh token store.
+	RefreshTokenID   uuid.UUID `json:"-"`
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
-func (s *Service) GenerateTokenPair(userID uuid.UUID, role string) (*TokenPair, error) {
-	accessToken, err := s.generateToken(userID, role, TokenTypeAccess, s.cfg.JWTAccessExpiry)
+// GenerateTokenPair mints an access/refresh pair for the given session.
+// sessionID identifies the refresh token family the pair belongs to and mfa
+// records whether the session passed a second factor.
+func (s *Service) GenerateTokenPair(userID uuid.UUID, role string, sessionID uuid.UUID, mfa bool) (*TokenPair, error) {
+	now := time.Now()
+
+	accessToken, err := s.generateToken(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.cfg.JWTAccessExpiry)),
+		},
+		UserID:    userID,
+		Role:      role,
+		TokenType: TokenTypeAccess,
+		SessionID: sessionID,
+		MFA:       mfa,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("generate access token: %w", err)
 	}
 
-	refreshToken, err := s.generateToken(userID, role, TokenTypeRefresh, s.cfg.JWTRefreshExpiry)
+	refreshID := uuid.New()
+	refreshExpiresAt := now.Add(s.cfg.JWTRefreshExpiry)
+	refreshToken, err := s.generateToken(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        refreshID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(refreshExpiresAt),
+		},
+		UserID:    userID,
+		Role:      role,
+		TokenType: TokenTypeRefresh,
+		SessionID: sessionID,
+		MFA:       mfa,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("generate refresh token: %w", err)
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshTokenID:   refreshID,
+		RefreshExpiresAt: refreshExpiresAt,
 	}, nil
 }
 
-func (s *Service) generateToken(userID uuid.UUID, role, tokenType string, expiry time.Duration) (string, error) {
+// GenerateImpersonationToken mints an access token that lets adminID act as
+// userID. There is no refresh token, so the session ends when it expires.
+func (s *Service) GenerateImpersonationToken(userID uuid.UUID, role string, adminID, sessionID uuid.UUID, expiresAt time.Time) (string, error) {
+	return s.generateToken(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		UserID:         userID,
+		Role:           role,
+		TokenType:      TokenTypeAccess,
+		SessionID:      sessionID,
+		ImpersonatorID: adminID,
+	})
+}
+
+// GenerateEmailVerificationToken signs a token proving ownership of email.
+// Binding the address means the link stops working if the email changes.
+func (s *Service) GenerateEmailVerificationToken(userID uuid.UUID, email string) (string, error) {
 	now := time.Now()
-	claims := Claims{
+	return s.generateToken(Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.cfg.EmailVerificationExpiry)),
 		},
 		UserID:    userID,
-		Role:      role,
-		TokenType: tokenType,
-	}
+		TokenType: TokenTypeEmailVerify,
+		Email:     email,
+	})
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString([]byte(s.cfg.JWTSecret))
-	if err != nil {
-		return "", err
+// GenerateEmailChangeToken signs a token confirming the user owns newEmail.
+// The current address is bound too, so the link is void once the email changes.
+func (s *Service) GenerateEmailChangeToken(userID uuid.UUID, currentEmail, newEmail string) (string, error) {
+	now := time.Now()
+	return s.generateToken(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.cfg.EmailVerificationExpiry)),
+		},
+		UserID:    userID,
+		TokenType: TokenTypeEmailChange,
+		Email:     currentEmail,
+		NewEmail:  newEmail,
+	})
+}
+
+// GenerateEmailChangeApprovalToken signs a token, mailed to the current
+// address, approving a change to newEmail for accounts without a password.
+func (s *Service) GenerateEmailChangeApprovalToken(userID uuid.UUID, currentEmail, newEmail string) (string, error) {
+	now := time.Now()
+	return s.generateToken(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.cfg.EmailVerificationExpiry)),
+		},
+		UserID:    userID,
+		TokenType: TokenTypeEmailChangeApproval,
+		Email:     currentEmail,
+		NewEmail:  newEmail,
+	})
+}
+
+// oauthStateExpiry bounds how long a user can sit on the provider consent page.
+const oauthStateExpiry = 10 * time.Minute
+
+// GenerateOAuthState signs the OAuth state parameter. linkUserID is set when an
+// authenticated user is linking a provider account, and uuid.Nil for sign-in.
+func (s *Service) GenerateOAuthState(linkUserID uuid.UUID) (string, error) {
+	now := time.Now()
+	return s.generateToken(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(oauthStateExpiry)),
+		},
+		UserID:    linkUserID,
+		TokenType: TokenTypeOAuthState,
+	})
+}
+
+// mfaChallengeExpiry is how long a user has to enter their TOTP code after
+// the password step of login.
+const mfaChallengeExpiry = 5 * time.Minute
+
+// GenerateMFAChallengeToken signs the token returned by the password step of
+// login when the user has two-factor authentication enabled.
+func (s *Service) GenerateMFAChallengeToken(userID uuid.UUID) (string, error) {
+	now := time.Now()
+	return s.generateToken(Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(mfaChallengeExpiry)),
+		},
+		UserID:    userID,
+		TokenType: TokenTypeMFA,
+	})
+}
+
+func (s *Service) generateToken(claims Claims) (string, error) {
+	if s.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(s.cfg.JWTSecret))
 	}
-	return signed, nil
+
+	token := jwt.NewWithClaims(s.keys.active.method, claims)
+	token.Header["kid"] = s.keys.active.kid
+	return token.SignedString(s.keys.active.private)
 }
 
 func (s *Service) ParseToken(tokenStr string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-		}
-		return []byte(s.cfg.JWTSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, s.verificationKey)
 	if err != nil {
 		return nil, err
 	}
@@ -77,3 +218,38 @@ func (s *Service) ParseToken(tokenStr string) (*Claims, error) {
 	}
 	return claims, nil
 }
+
+// verificationKey picks the key to verify a token with from its kid header.
+// Tokens without a kid predate key rotation and are checked against JWT_SECRET.
+func (s *Service) verificationKey(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		if s.cfg.JWTSecret == "" {
+			return nil, fmt.Errorf("token has no kid")
+		}
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(s.cfg.JWTSecret), nil
+	}
+
+	if s.keys == nil {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	key, ok := s.keys.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	}
+	return key.public, nil
+}
+
+// JWKS returns the public verification keys for /.well-known/jwks.json.
+func (s *Service) JWKS() []JWK {
+	if s.keys == nil {
+		return []JWK{}
+	}
+	return s.keys.JWKS()
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey is one entry in the key set. private is nil for keys that are
+// only kept around to verify tokens issued before a rotation.
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// KeySet holds every key tokens may be verified with and the one new tokens
+// are signed with.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// LoadKeySet reads PEM keys from dir. A file named <kid>.pem holds a PKCS#8
+// (or PKCS#1 RSA) private key usable for signing; <kid>.pub.pem holds a PKIX
+// public key for a retired key that still verifies. RSA keys sign with RS256
+// and Ed25519 keys with EdDSA. If activeKID is empty the lexically greatest
+// private key is used, so date-prefixed names rotate automatically.
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read key dir: %w", err)
+	}
+
+	ks := &KeySet{keys: make(map[string]*signingKey)}
+	var signers []string
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("read key %s: %w", name, err)
+		}
+
+		var key *signingKey
+		if kid, ok := strings.CutSuffix(name, ".pub.pem"); ok {
+			key, err = parsePublicKey(kid, data)
+		} else {
+			key, err = parsePrivateKey(strings.TrimSuffix(name, ".pem"), data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse key %s: %w", name, err)
+		}
+
+		if _, dup := ks.keys[key.kid]; dup {
+			return nil, fmt.Errorf("duplicate key id %q", key.kid)
+		}
+		ks.keys[key.kid] = key
+		if key.private != nil {
+			signers = append(signers, key.kid)
+		}
+	}
+
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no private keys found in %s", dir)
+	}
+
+	if activeKID == "" {
+		sort.Strings(signers)
+		activeKID = signers[len(signers)-1]
+	}
+	active, ok := ks.keys[activeKID]
+	if !ok || active.private == nil {
+		return nil, fmt.Errorf("active key %q has no private key in %s", activeKID, dir)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+func parsePrivateKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &signingKey{kid: kid, method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &signingKey{kid: kid, method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+}
+
+func parsePublicKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PublicKey:
+		return &signingKey{kid: kid, method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PublicKey:
+		return &signingKey{kid: kid, method: jwt.SigningMethodEdDSA, public: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", parsed)
+	}
+}
+
+// JWK is a single public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// OKP (Ed25519)
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS returns the public half of every key, sorted by key ID.
+func (ks *KeySet) JWKS() []JWK {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := make([]JWK, 0, len(kids))
+	for _, kid := range kids {
+		key := ks.keys[kid]
+		jwk := JWK{KeyID: kid, Use: "sig", Algorithm: key.method.Alg()}
+
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		jwks = append(jwks, jwk)
+	}
+	return jwks
+}
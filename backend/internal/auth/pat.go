@@ -0,0 +1,31 @@
+package auth
+
+import "slices"
+
+// Personal access token scopes. Tokens only reach routes that declare one of
+// their scopes; account, payment and admin routes accept sessions only.
+const (
+	ScopeRead        = "read"
+	ScopeProgress    = "progress"
+	ScopeSubmissions = "submissions"
+)
+
+// PersonalAccessTokenPrefix marks a bearer token as a personal access token
+// rather than a JWT, and makes leaked tokens easy to grep for.
+const PersonalAccessTokenPrefix = "lup_"
+
+var validScopes = []string{ScopeRead, ScopeProgress, ScopeSubmissions}
+
+// ValidScope reports whether scope is a known personal access token scope.
+func ValidScope(scope string) bool {
+	return slices.Contains(validScopes, scope)
+}
+
+// GeneratePersonalAccessToken returns a new random personal access token.
+func GeneratePersonalAccessToken() (string, error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	return PersonalAccessTokenPrefix + token, nil
+}
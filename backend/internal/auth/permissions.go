@@ -0,0 +1,17 @@
+package auth
+
+// Built-in roles. Staff roles and what they may do live in the roles and
+// role_permissions tables; more can be added there without a deploy.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Permissions checked by RequirePermission and the handlers.
+const (
+	PermSubmissionsRead   = "submissions:read"
+	PermSubmissionsReview = "submissions:review"
+	PermContentEdit       = "content:edit"
+	PermUsersManage       = "users:manage"
+	PermUsersImpersonate  = "users:impersonate"
+)
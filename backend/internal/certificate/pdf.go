@@ -0,0 +1,123 @@
+// Package certificate renders module completion certificates as PDF. The PDF
+// is written by hand: one landscape A4 page using the standard Helvetica
+// fonts, which every viewer has, so nothing needs to be embedded.
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Certificate is what gets printed.
+type Certificate struct {
+	ID            string
+	RecipientName string
+	ModuleTitle   string
+	IssuedAt      time.Time
+	VerifyURL     string
+}
+
+// A4 landscape, in points.
+const (
+	pageWidth  = 842
+	pageHeight = 595
+	margin     = 36
+	// Text is kept inside the inner border with some room to spare.
+	maxTextWidth = pageWidth - 4*margin
+)
+
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// PDF renders the certificate.
+func PDF(c Certificate) []byte {
+	var content bytes.Buffer
+
+	// Double border in a muted blue.
+	fmt.Fprintf(&content, "0.16 0.33 0.55 RG 3 w %d %d %d %d re S\n",
+		margin, margin, pageWidth-2*margin, pageHeight-2*margin)
+	fmt.Fprintf(&content, "1 w %d %d %d %d re S\n",
+		margin+8, margin+8, pageWidth-2*margin-16, pageHeight-2*margin-16)
+
+	centered(&content, fontBold, 34, 460, "Certificate of Completion")
+	centered(&content, fontRegular, 14, 400, "This certifies that")
+	centered(&content, fontBold, 30, 350, c.RecipientName)
+	centered(&content, fontRegular, 14, 300, "has successfully completed the module")
+	centered(&content, fontBold, 22, 255, c.ModuleTitle)
+	centered(&content, fontRegular, 12, 190, "Issued "+c.IssuedAt.UTC().Format("January 2, 2006"))
+	centered(&content, fontRegular, 9, 90, "Certificate ID "+c.ID)
+	centered(&content, fontRegular, 9, 76, "Verify at "+c.VerifyURL)
+
+	return document(content.Bytes())
+}
+
+// centered writes one line of text centred on the page, shrinking the font
+// until it fits.
+func centered(w *bytes.Buffer, font string, size float64, y int, text string) {
+	encoded := winAnsi(text)
+	width := textWidth(font, encoded) * size / 1000
+	if width > maxTextWidth {
+		size *= maxTextWidth / width
+		width = maxTextWidth
+	}
+	x := (pageWidth - width) / 2
+
+	fmt.Fprintf(w, "BT /%s %.2f Tf 0.1 0.1 0.1 rg %.2f %d Td (%s) Tj ET\n",
+		font, size, x, y, escape(encoded))
+}
+
+// document wraps a page content stream in the minimal PDF structure: catalog,
+// page tree, page, fonts and content, followed by the cross-reference table.
+func document(content []byte) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /%s 4 0 R /%s 5 0 R >> >> /Contents 6 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// winAnsi encodes s for the WinAnsiEncoding fonts. It agrees with Latin-1
+// outside 0x80-0x9F; anything else is replaced with '?'.
+func winAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+
+func escape(b []byte) string {
+	return stringEscaper.Replace(string(b))
+}
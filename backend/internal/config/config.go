@@ -10,19 +10,42 @@ import (
 )
 
 type Config struct {
-	Port string
-	Env  string
+	Port       string
+	Env        string
+	AppBaseURL string
 
 	DatabaseURL string
 
 	JWTSecret        string
+	JWTKeyDir        string
+	JWTActiveKeyID   string
 	JWTAccessExpiry  time.Duration
 	JWTRefreshExpiry time.Duration
 
+	PasswordResetExpiry     time.Duration
+	EmailVerificationExpiry time.Duration
+
+	LoginMaxAttempts   int
+	LoginAttemptWindow time.Duration
+	LoginLockoutBase   time.Duration
+	LoginLockoutMax    time.Duration
+
+	// RequireAdminMFA blocks admin routes for sessions without 2FA.
+	RequireAdminMFA bool
+
+	ImpersonationExpiry time.Duration
+
 	StripeSecretKey     string
 	StripeWebhookSecret string
 	StripePriceID       string
 
+	GitHubClientID     string
+	GitHubClientSecret string
+	GitHubRedirectURL  string
+	GitHubAuthURL      string
+	GitHubTokenURL     string
+	GitHubAPIURL       string
+
 	SMTPHost     string
 	SMTPPort     int
 	SMTPUser     string
@@ -37,16 +60,37 @@ func Load() (*Config, error) {
 	cfg := &Config{
 		Port:        getEnv("PORT", "8080"),
 		Env:         getEnv("ENV", "development"),
+		AppBaseURL:  getEnv("APP_BASE_URL", "http://localhost:3000"),
 		DatabaseURL: requireEnv("DATABASE_URL"),
 
-		JWTSecret:        requireEnv("JWT_SECRET"),
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTKeyDir:        getEnv("JWT_KEY_DIR", ""),
+		JWTActiveKeyID:   getEnv("JWT_ACTIVE_KEY_ID", ""),
 		JWTAccessExpiry:  parseDuration("JWT_ACCESS_EXPIRY", 15*time.Minute),
 		JWTRefreshExpiry: parseDuration("JWT_REFRESH_EXPIRY", 7*24*time.Hour),
 
+		PasswordResetExpiry:     parseDuration("PASSWORD_RESET_EXPIRY", time.Hour),
+		EmailVerificationExpiry: parseDuration("EMAIL_VERIFICATION_EXPIRY", 24*time.Hour),
+
+		LoginMaxAttempts:   parseInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginAttemptWindow: parseDuration("LOGIN_ATTEMPT_WINDOW", 24*time.Hour),
+		LoginLockoutBase:   parseDuration("LOGIN_LOCKOUT_BASE", time.Minute),
+		LoginLockoutMax:    parseDuration("LOGIN_LOCKOUT_MAX", 24*time.Hour),
+
+		RequireAdminMFA: parseBool("REQUIRE_ADMIN_MFA", false),
+
+		ImpersonationExpiry: parseDuration("IMPERSONATION_EXPIRY", 15*time.Minute),
+
 		StripeSecretKey:     requireEnv("STRIPE_SECRET_KEY"),
 		StripeWebhookSecret: requireEnv("STRIPE_WEBHOOK_SECRET"),
 		StripePriceID:       requireEnv("STRIPE_PRICE_ID"),
 
+		GitHubClientID:     getEnv("GITHUB_CLIENT_ID", ""),
+		GitHubClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
+		GitHubAuthURL:      getEnv("GITHUB_AUTH_URL", "https://github.com/login/oauth/authorize"),
+		GitHubTokenURL:     getEnv("GITHUB_TOKEN_URL", "https://github.com/login/oauth/access_token"),
+		GitHubAPIURL:       getEnv("GITHUB_API_URL", "https://api.github.com"),
+
 		SMTPHost:     getEnv("SMTP_HOST", "localhost"),
 		SMTPPort:     parseInt("SMTP_PORT", 1025),
 		SMTPUser:     getEnv("SMTP_USER", ""),
@@ -54,13 +98,28 @@ func Load() (*Config, error) {
 		EmailFrom:    getEnv("EMAIL_FROM", "noreply@levelup.dev"),
 	}
 
+	// The callback lives on the frontend, which posts the code back to us.
+	cfg.GitHubRedirectURL = getEnv("GITHUB_REDIRECT_URL", cfg.AppBaseURL+"/auth/github/callback")
+
 	return cfg, nil
 }
 
+// LoadDatabaseURL reads just DATABASE_URL, for CLI commands that need the
+// database but none of the server's other settings.
+func LoadDatabaseURL() string {
+	_ = godotenv.Load()
+	return requireEnv("DATABASE_URL")
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.Env == "development"
 }
 
+// GitHubEnabled reports whether GitHub sign-in has been configured.
+func (c *Config) GitHubEnabled() bool {
+	return c.GitHubClientID != "" && c.GitHubClientSecret != ""
+}
+
 func requireEnv(key string) string {
 	v := os.Getenv(key)
 	if v == "" {
@@ -99,3 +158,15 @@ func parseInt(key string, fallback int) int {
 	}
 	return n
 }
+
+func parseBool(key string, fallback bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
@@ -0,0 +1,299 @@
+// Package content reads and writes the curriculum as a directory tree, so
+// lessons can be authored and reviewed as Markdown instead of SQL literals.
+//
+// The layout is one directory per module:
+//
+//	<dir>/
+//	  go-concurrency/
+//	    module.yaml        module fields, skills and assignment
+//	    worker-pools.md    one file per lesson, YAML front matter + Markdown
+//	    context.md
+//	  distributed-systems/
+//	    ...
+//
+// Directories without a module.yaml are ignored.
+package content
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const moduleFile = "module.yaml"
+
+const frontMatterDelim = "---\n"
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+type Module struct {
+	Slug           string      `yaml:"slug"`
+	Title          string      `yaml:"title"`
+	Description    string      `yaml:"description"`
+	Order          int32       `yaml:"order"`
+	EstimatedHours float64     `yaml:"estimated_hours"`
+	Status         string      `yaml:"status,omitempty"`
+	PublishAt      *time.Time  `yaml:"publish_at,omitempty"`
+	Skills         []string    `yaml:"skills,omitempty"`
+	Assignment     *Assignment `yaml:"assignment,omitempty"`
+
+	Lessons []Lesson `yaml:"-"`
+}
+
+type Assignment struct {
+	Title          string  `yaml:"title"`
+	Description    string  `yaml:"description"`
+	Rubric         string  `yaml:"rubric"`
+	EstimatedHours float64 `yaml:"estimated_hours"`
+}
+
+// Lesson is a lesson's front matter; Content is the Markdown body below it.
+type Lesson struct {
+	Slug             string     `yaml:"slug"`
+	Title            string     `yaml:"title"`
+	Order            int32      `yaml:"order"`
+	EstimatedMinutes int32      `yaml:"estimated_minutes"`
+	Preview          bool       `yaml:"preview,omitempty"`
+	Status           string     `yaml:"status,omitempty"`
+	PublishAt        *time.Time `yaml:"publish_at,omitempty"`
+
+	Content string `yaml:"-"`
+}
+
+// Read loads and validates every module under dir. All validation problems
+// are reported together so authors can fix them in one pass.
+func Read(dir string) ([]Module, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []Module
+	var problems []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		moduleDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(moduleDir, moduleFile)); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		module, err := readModule(moduleDir)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		modules = append(modules, module)
+	}
+
+	problems = append(problems, checkModules(modules)...)
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Order < modules[j].Order })
+	return modules, nil
+}
+
+func readModule(moduleDir string) (Module, error) {
+	path := filepath.Join(moduleDir, moduleFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Module{}, err
+	}
+
+	var module Module
+	if err := decodeYAML(data, &module); err != nil {
+		return Module{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if module.Slug == "" {
+		module.Slug = filepath.Base(moduleDir)
+	}
+	if module.Status == "" {
+		module.Status = "published"
+	}
+
+	var problems []error
+	if err := checkFields(module.Slug, module.Title, module.Order, module.Status, module.PublishAt); err != nil {
+		problems = append(problems, fmt.Errorf("%s: %w", path, err))
+	}
+	if module.Assignment != nil && strings.TrimSpace(module.Assignment.Title) == "" {
+		problems = append(problems, fmt.Errorf("%s: assignment title is required", path))
+	}
+
+	files, err := filepath.Glob(filepath.Join(moduleDir, "*.md"))
+	if err != nil {
+		return Module{}, err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		lesson, err := readLesson(file)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		module.Lessons = append(module.Lessons, lesson)
+	}
+
+	if len(problems) > 0 {
+		return Module{}, errors.Join(problems...)
+	}
+
+	sort.Slice(module.Lessons, func(i, j int) bool { return module.Lessons[i].Order < module.Lessons[j].Order })
+	return module, nil
+}
+
+func readLesson(path string) (Lesson, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Lesson{}, err
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	if !strings.HasPrefix(text, frontMatterDelim) {
+		return Lesson{}, fmt.Errorf("%s: missing front matter", path)
+	}
+	frontMatter, body, ok := strings.Cut("\n"+text[len(frontMatterDelim):], "\n"+frontMatterDelim)
+	if !ok {
+		return Lesson{}, fmt.Errorf("%s: unterminated front matter", path)
+	}
+
+	var lesson Lesson
+	if err := decodeYAML([]byte(frontMatter), &lesson); err != nil {
+		return Lesson{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if lesson.Slug == "" {
+		lesson.Slug = strings.TrimSuffix(filepath.Base(path), ".md")
+	}
+	if lesson.Status == "" {
+		lesson.Status = "published"
+	}
+	lesson.Content = strings.TrimLeft(body, "\n")
+
+	if err := checkFields(lesson.Slug, lesson.Title, lesson.Order, lesson.Status, lesson.PublishAt); err != nil {
+		return Lesson{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if lesson.EstimatedMinutes < 0 {
+		return Lesson{}, fmt.Errorf("%s: estimated_minutes must not be negative", path)
+	}
+	return lesson, nil
+}
+
+// decodeYAML rejects unknown keys so a typo in a field name is not silently
+// dropped.
+func decodeYAML(data []byte, v any) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(v)
+}
+
+func checkFields(slug, title string, order int32, status string, publishAt *time.Time) error {
+	switch {
+	case !slugPattern.MatchString(slug):
+		return fmt.Errorf("invalid slug %q: use lowercase letters, digits and hyphens", slug)
+	case strings.TrimSpace(title) == "":
+		return errors.New("title is required")
+	case order < 1:
+		return errors.New("order must be at least 1")
+	}
+
+	switch status {
+	case "draft", "published", "archived":
+	case "scheduled":
+		if publishAt == nil {
+			return errors.New("publish_at is required when status is scheduled")
+		}
+	default:
+		return fmt.Errorf("invalid status %q: must be draft, scheduled, published, or archived", status)
+	}
+	return nil
+}
+
+// checkModules catches duplicates that only show up across files.
+func checkModules(modules []Module) []error {
+	var problems []error
+	slugs := make(map[string]bool)
+	for _, m := range modules {
+		if slugs[m.Slug] {
+			problems = append(problems, fmt.Errorf("duplicate module slug %q", m.Slug))
+		}
+		slugs[m.Slug] = true
+
+		lessonSlugs := make(map[string]bool)
+		for _, l := range m.Lessons {
+			if lessonSlugs[l.Slug] {
+				problems = append(problems, fmt.Errorf("module %q: duplicate lesson slug %q", m.Slug, l.Slug))
+			}
+			lessonSlugs[l.Slug] = true
+		}
+
+		skills := make(map[string]bool)
+		for _, s := range m.Skills {
+			if skills[s] {
+				problems = append(problems, fmt.Errorf("module %q: duplicate skill %q", m.Slug, s))
+			}
+			skills[s] = true
+		}
+	}
+	return problems
+}
+
+// Write stores modules under dir in the layout Read expects, one directory
+// per module named after its slug. Existing files are overwritten.
+func Write(dir string, modules []Module) error {
+	for _, module := range modules {
+		moduleDir := filepath.Join(dir, module.Slug)
+		if err := os.MkdirAll(moduleDir, 0o755); err != nil {
+			return err
+		}
+
+		data, err := encodeYAML(module)
+		if err != nil {
+			return fmt.Errorf("encode module %q: %w", module.Slug, err)
+		}
+		if err := os.WriteFile(filepath.Join(moduleDir, moduleFile), data, 0o644); err != nil {
+			return err
+		}
+
+		for _, lesson := range module.Lessons {
+			frontMatter, err := encodeYAML(lesson)
+			if err != nil {
+				return fmt.Errorf("encode lesson %q: %w", lesson.Slug, err)
+			}
+
+			var buf bytes.Buffer
+			buf.WriteString(frontMatterDelim)
+			buf.Write(frontMatter)
+			buf.WriteString(frontMatterDelim)
+			buf.WriteString("\n")
+			buf.WriteString(lesson.Content)
+
+			if err := os.WriteFile(filepath.Join(moduleDir, lesson.Slug+".md"), buf.Bytes(), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func encodeYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
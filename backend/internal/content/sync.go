@@ -0,0 +1,336 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	appdb "github.com/anujgupta/level-up-backend/internal/db"
+)
+
+// Summary counts what an import wrote. Revisions only counts lessons whose
+// title or content changed.
+type Summary struct {
+	Modules   int
+	Lessons   int
+	Skills    int
+	Revisions int
+
+	// Stale lists content that exists in the database but not in the source
+	// tree. Import never deletes it: learners may have progress against it.
+	Stale []string
+}
+
+// Import upserts modules into the database in a single transaction, keyed by
+// slug. Running it twice on the same tree is a no-op: a lesson only gets a new
+// revision when its title or content actually changed.
+func Import(ctx context.Context, pool *pgxpool.Pool, modules []Module) (Summary, error) {
+	var summary Summary
+	err := appdb.WithTx(ctx, pool, func(tx pgx.Tx) error {
+		q := dbgen.New(tx)
+
+		slugs := make(map[string]bool)
+		for _, m := range modules {
+			if err := importModule(ctx, q, m, &summary); err != nil {
+				return fmt.Errorf("module %q: %w", m.Slug, err)
+			}
+			slugs[m.Slug] = true
+		}
+
+		existing, err := q.ListModules(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range existing {
+			if !slugs[m.Slug] {
+				summary.Stale = append(summary.Stale, "module "+m.Slug)
+			}
+		}
+		return nil
+	})
+	return summary, err
+}
+
+func importModule(ctx context.Context, q *dbgen.Queries, m Module, summary *Summary) error {
+	hours, err := numericFromFloat(m.EstimatedHours)
+	if err != nil {
+		return fmt.Errorf("invalid estimated_hours: %w", err)
+	}
+
+	module, err := q.UpsertModule(ctx, dbgen.UpsertModuleParams{
+		Title:          m.Title,
+		Slug:           m.Slug,
+		Description:    m.Description,
+		EstimatedHours: hours,
+		OrderIndex:     m.Order,
+	})
+	if err != nil {
+		return err
+	}
+	status := dbgen.ContentStatus(m.Status)
+	if _, err := q.SetModuleStatus(ctx, dbgen.SetModuleStatusParams{
+		ID:        module.ID,
+		Status:    status,
+		PublishAt: publishAt(status, m.PublishAt, module.PublishAt),
+	}); err != nil {
+		return err
+	}
+	summary.Modules++
+
+	slugs := make(map[string]bool)
+	for _, l := range m.Lessons {
+		if err := importLesson(ctx, q, module.ID, l, summary); err != nil {
+			return fmt.Errorf("lesson %q: %w", l.Slug, err)
+		}
+		slugs[l.Slug] = true
+	}
+
+	lessons, err := q.GetLessonsByModule(ctx, module.ID)
+	if err != nil {
+		return err
+	}
+	for _, l := range lessons {
+		if !slugs[l.Slug] {
+			summary.Stale = append(summary.Stale, fmt.Sprintf("lesson %s/%s", m.Slug, l.Slug))
+		}
+	}
+
+	if err := importSkills(ctx, q, module.ID, m, summary); err != nil {
+		return err
+	}
+
+	if m.Assignment == nil {
+		if _, err := q.GetAssignmentByModuleID(ctx, module.ID); err == nil {
+			summary.Stale = append(summary.Stale, "assignment "+m.Slug)
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		return nil
+	}
+
+	assignmentHours, err := numericFromFloat(m.Assignment.EstimatedHours)
+	if err != nil {
+		return fmt.Errorf("invalid assignment estimated_hours: %w", err)
+	}
+	_, err = q.UpsertAssignment(ctx, dbgen.UpsertAssignmentParams{
+		ModuleID:       module.ID,
+		Title:          m.Assignment.Title,
+		Description:    m.Assignment.Description,
+		Rubric:         m.Assignment.Rubric,
+		EstimatedHours: assignmentHours,
+	})
+	return err
+}
+
+func importLesson(ctx context.Context, q *dbgen.Queries, moduleID uuid.UUID, l Lesson, summary *Summary) error {
+	lesson, err := q.UpsertLesson(ctx, dbgen.UpsertLessonParams{
+		ModuleID:         moduleID,
+		Title:            l.Title,
+		Slug:             l.Slug,
+		Content:          l.Content,
+		EstimatedMinutes: l.EstimatedMinutes,
+		IsPreview:        l.Preview,
+		OrderIndex:       l.Order,
+	})
+	if err != nil {
+		return err
+	}
+	summary.Lessons++
+
+	latest, err := q.GetLatestLessonRevision(ctx, lesson.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	if errors.Is(err, pgx.ErrNoRows) || latest.Title != lesson.Title || latest.Content != lesson.Content {
+		latest, err = q.CreateLessonRevision(ctx, dbgen.CreateLessonRevisionParams{
+			LessonID: lesson.ID,
+			Title:    lesson.Title,
+			Content:  lesson.Content,
+		})
+		if err != nil {
+			return err
+		}
+		summary.Revisions++
+	}
+
+	status := dbgen.ContentStatus(l.Status)
+	params := dbgen.SetLessonStatusParams{
+		ID:        lesson.ID,
+		Status:    status,
+		PublishAt: publishAt(status, l.PublishAt, lesson.PublishAt),
+	}
+	if status == dbgen.ContentStatusPublished || status == dbgen.ContentStatusScheduled {
+		params.PublishedRevisionID = pgtype.UUID{Bytes: latest.ID, Valid: true}
+	}
+	_, err = q.SetLessonStatus(ctx, params)
+	return err
+}
+
+// importSkills matches skills by name, since they have no slug. Skills are
+// reordered to match the file; renaming one in the file adds a new skill and
+// reports the old one as stale.
+func importSkills(ctx context.Context, q *dbgen.Queries, moduleID uuid.UUID, m Module, summary *Summary) error {
+	existing, err := q.GetSkillsByModule(ctx, moduleID)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]dbgen.Skill, len(existing))
+	for _, s := range existing {
+		byName[s.SkillName] = s
+	}
+
+	listed := make(map[string]bool, len(m.Skills))
+	for i, name := range m.Skills {
+		skill, ok := byName[name]
+		if !ok {
+			skill, err = q.CreateSkill(ctx, dbgen.CreateSkillParams{
+				ModuleID:  moduleID,
+				SkillName: name,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if err := q.SetSkillOrderIndex(ctx, dbgen.SetSkillOrderIndexParams{
+			ID:         skill.ID,
+			OrderIndex: int32(i + 1),
+		}); err != nil {
+			return err
+		}
+		listed[name] = true
+		summary.Skills++
+	}
+
+	for _, s := range existing {
+		if !listed[s.SkillName] {
+			summary.Stale = append(summary.Stale, fmt.Sprintf("skill %s: %s", m.Slug, s.SkillName))
+		}
+	}
+	return nil
+}
+
+// Export reads the current curriculum back out of the database, including
+// drafts. Published and scheduled lessons are exported as published, since
+// importing them publishes what the file says; other lessons are exported
+// from their working copy.
+func Export(ctx context.Context, q *dbgen.Queries) ([]Module, error) {
+	rows, err := q.ListModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]Module, 0, len(rows))
+	for _, row := range rows {
+		module := Module{
+			Slug:           row.Slug,
+			Title:          row.Title,
+			Description:    row.Description,
+			Order:          row.OrderIndex,
+			EstimatedHours: floatFromNumeric(row.EstimatedHours),
+			Status:         string(row.Status),
+			PublishAt:      exportedPublishAt(row.Status, row.PublishAt),
+		}
+
+		lessons, err := q.GetLessonsByModule(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range lessons {
+			title, content := l.Title, l.Content
+			if (l.Status == dbgen.ContentStatusPublished || l.Status == dbgen.ContentStatusScheduled) &&
+				l.PublishedRevisionID.Valid {
+				published, err := q.GetLessonRevisionByID(ctx, l.PublishedRevisionID.Bytes)
+				if err != nil {
+					return nil, err
+				}
+				title, content = published.Title, published.Content
+			}
+
+			module.Lessons = append(module.Lessons, Lesson{
+				Slug:             l.Slug,
+				Title:            title,
+				Order:            l.OrderIndex,
+				EstimatedMinutes: l.EstimatedMinutes,
+				Preview:          l.IsPreview,
+				Status:           string(l.Status),
+				PublishAt:        exportedPublishAt(l.Status, l.PublishAt),
+				Content:          content,
+			})
+		}
+
+		skills, err := q.GetSkillsByModule(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range skills {
+			module.Skills = append(module.Skills, s.SkillName)
+		}
+
+		assignment, err := q.GetAssignmentByModuleID(ctx, row.ID)
+		switch {
+		case err == nil:
+			module.Assignment = &Assignment{
+				Title:          assignment.Title,
+				Description:    assignment.Description,
+				Rubric:         assignment.Rubric,
+				EstimatedHours: floatFromNumeric(assignment.EstimatedHours),
+			}
+		case !errors.Is(err, pgx.ErrNoRows):
+			return nil, err
+		}
+
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+// exportedPublishAt only writes publish_at where it means something to an
+// author: the go-live time of scheduled content.
+func exportedPublishAt(status dbgen.ContentStatus, at pgtype.Timestamptz) *time.Time {
+	if status != dbgen.ContentStatusScheduled || !at.Valid {
+		return nil
+	}
+	t := at.Time.UTC()
+	return &t
+}
+
+// publishAt works out the publish time to store for status. An explicit time
+// from the source wins; otherwise a published item keeps the time it was first
+// published, so re-importing doesn't bump it.
+func publishAt(status dbgen.ContentStatus, want *time.Time, current pgtype.Timestamptz) pgtype.Timestamptz {
+	switch status {
+	case dbgen.ContentStatusScheduled, dbgen.ContentStatusPublished:
+		if want != nil {
+			return pgtype.Timestamptz{Time: *want, Valid: true}
+		}
+		if status == dbgen.ContentStatusPublished {
+			if current.Valid {
+				return current
+			}
+			return pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		}
+	}
+	return pgtype.Timestamptz{}
+}
+
+func numericFromFloat(f float64) (pgtype.Numeric, error) {
+	var n pgtype.Numeric
+	err := n.Scan(strconv.FormatFloat(f, 'f', 1, 64))
+	return n, err
+}
+
+func floatFromNumeric(n pgtype.Numeric) float64 {
+	f, err := n.Float64Value()
+	if err != nil {
+		return 0
+	}
+	return f.Float64
+}
@@ -0,0 +1,131 @@
+// Package diff computes line-based differences between two texts.
+package diff
+
+import (
+	"errors"
+	"strings"
+)
+
+// Op says what happened to a line going from the old text to the new one.
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Line is one line of a diff.
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// MaxChangedLines bounds how many lines, old and new together, may differ
+// between the two texts once their common prefix and suffix are removed. The
+// diff needs memory proportional to the product of the two line counts.
+const MaxChangedLines = 4000
+
+// ErrTooLarge is returned by Lines when the texts differ in more than
+// MaxChangedLines lines.
+var ErrTooLarge = errors.New("diff: too many changed lines")
+
+// Lines returns the shortest edit script turning a into b, one entry per line,
+// computed from the longest common subsequence of their lines.
+func Lines(a, b string) ([]Line, error) {
+	x := splitLines(a)
+	y := splitLines(b)
+
+	// Lines shared at the start and end are always part of the LCS, so only
+	// the middle needs the quadratic table.
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix &&
+		x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+	mx := x[prefix : len(x)-suffix]
+	my := y[prefix : len(y)-suffix]
+	if len(mx)+len(my) > MaxChangedLines {
+		return nil, ErrTooLarge
+	}
+
+	out := make([]Line, 0, max(len(x), len(y)))
+	for _, l := range x[:prefix] {
+		out = append(out, Line{Op: OpEqual, Text: l})
+	}
+	out = appendLCS(out, mx, my)
+	for _, l := range x[len(x)-suffix:] {
+		out = append(out, Line{Op: OpEqual, Text: l})
+	}
+	return out, nil
+}
+
+// appendLCS appends the edit script turning x into y to out.
+func appendLCS(out []Line, x, y []string) []Line {
+	// lcs[i][j] is the LCS length of x[i:] and y[j:].
+	lcs := make([][]int32, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, Line{Op: OpEqual, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, Line{Op: OpDelete, Text: x[i]})
+			i++
+		default:
+			out = append(out, Line{Op: OpInsert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, Line{Op: OpDelete, Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		out = append(out, Line{Op: OpInsert, Text: y[j]})
+	}
+	return out
+}
+
+// Unified renders lines in the familiar "+"/"-"/" " prefixed form.
+func Unified(lines []Line) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		switch l.Op {
+		case OpInsert:
+			sb.WriteByte('+')
+		case OpDelete:
+			sb.WriteByte('-')
+		default:
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(l.Text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,88 @@
+package diff
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{name: "both empty", a: "", b: "", want: []Line{}},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "one\ntwo\n",
+			want: []Line{{OpInsert, "one"}, {OpInsert, "two"}},
+		},
+		{
+			name: "to empty",
+			a:    "one\ntwo",
+			b:    "",
+			want: []Line{{OpDelete, "one"}, {OpDelete, "two"}},
+		},
+		{
+			name: "unchanged",
+			a:    "one\ntwo\n",
+			b:    "one\ntwo",
+			want: []Line{{OpEqual, "one"}, {OpEqual, "two"}},
+		},
+		{
+			name: "changed line",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []Line{{OpEqual, "one"}, {OpDelete, "two"}, {OpInsert, "2"}, {OpEqual, "three"}},
+		},
+		{
+			name: "insert and delete",
+			a:    "a\nb\nc",
+			b:    "b\nc\nd",
+			want: []Line{{OpDelete, "a"}, {OpEqual, "b"}, {OpEqual, "c"}, {OpInsert, "d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Lines(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Lines(%q, %q): %v", tt.a, tt.b, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesTooLarge(t *testing.T) {
+	old := strings.Repeat("old\n", MaxChangedLines/2+1)
+	changed := strings.Repeat("new\n", MaxChangedLines/2)
+	if _, err := Lines(old, changed); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Lines() error = %v, want ErrTooLarge", err)
+	}
+
+	// Unchanged lines around the edit don't count towards the limit.
+	same := strings.Repeat("same\n", MaxChangedLines)
+	got, err := Lines(same+"old\n"+same, same+"new\n"+same)
+	if err != nil {
+		t.Fatalf("Lines() with a small edit: %v", err)
+	}
+	if n := len(got); n != 2*MaxChangedLines+2 {
+		t.Errorf("Lines() returned %d lines, want %d", n, 2*MaxChangedLines+2)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	lines := []Line{{OpEqual, "one"}, {OpDelete, "two"}, {OpInsert, "2"}}
+	want := " one\n-two\n+2\n"
+	if got := Unified(lines); got != want {
+		t.Errorf("Unified() = %q, want %q", got, want)
+	}
+	if got := Unified(nil); got != "" {
+		t.Errorf("Unified(nil) = %q, want empty", got)
+	}
+}
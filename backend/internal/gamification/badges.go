@@ -0,0 +1,89 @@
+package gamification
+
+// Stats are the counters badge rules look at.
+type Stats struct {
+	LessonsCompleted    int
+	SkillsCompleted     int
+	ModulesCompleted    int
+	ApprovedSubmissions int
+	CurrentStreak       int
+	XP                  int
+}
+
+// Badge is an achievement. Earned decides from the user's stats whether they
+// have it; once granted a badge is kept even if the stats later drop, e.g.
+// after a module reset.
+type Badge struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Earned      func(Stats) bool `json:"-"`
+}
+
+// Badges is every badge, in the order they're listed to users. IDs are stored
+// in the database, so never rename one.
+var Badges = []Badge{
+	{
+		ID:          "first-lesson",
+		Name:        "First Steps",
+		Description: "Complete your first lesson",
+		Earned:      func(s Stats) bool { return s.LessonsCompleted >= 1 },
+	},
+	{
+		ID:          "module-finisher",
+		Name:        "Module Finisher",
+		Description: "Complete every lesson in a module",
+		Earned:      func(s Stats) bool { return s.ModulesCompleted >= 1 },
+	},
+	{
+		ID:          "first-approved-assignment",
+		Name:        "Shipped It",
+		Description: "Get your first assignment approved",
+		Earned:      func(s Stats) bool { return s.ApprovedSubmissions >= 1 },
+	},
+	{
+		ID:          "skill-collector",
+		Name:        "Skill Collector",
+		Description: "Check off 25 skills",
+		Earned:      func(s Stats) bool { return s.SkillsCompleted >= 25 },
+	},
+	{
+		ID:          "streak-7",
+		Name:        "On a Roll",
+		Description: "Learn 7 days in a row",
+		Earned:      func(s Stats) bool { return s.CurrentStreak >= 7 },
+	},
+	{
+		ID:          "streak-30",
+		Name:        "Unstoppable",
+		Description: "Learn 30 days in a row",
+		Earned:      func(s Stats) bool { return s.CurrentStreak >= 30 },
+	},
+	{
+		ID:          "level-5",
+		Name:        "Rising Star",
+		Description: "Reach level 5",
+		Earned:      func(s Stats) bool { return LevelFor(s.XP).Level >= 5 },
+	},
+}
+
+// BadgeByID looks up a badge definition.
+func BadgeByID(id string) (Badge, bool) {
+	for _, b := range Badges {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return Badge{}, false
+}
+
+// NewlyEarned returns the badges the stats qualify for that aren't in owned.
+func NewlyEarned(s Stats, owned map[string]bool) []Badge {
+	var earned []Badge
+	for _, b := range Badges {
+		if !owned[b.ID] && b.Earned(s) {
+			earned = append(earned, b)
+		}
+	}
+	return earned
+}
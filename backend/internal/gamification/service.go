@@ -0,0 +1,170 @@
+package gamification
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/activity"
+)
+
+// Service records XP and badges. Call Award after each progress event; it is
+// idempotent, so retrying a request never awards twice.
+type Service struct {
+	queries *dbgen.Queries
+}
+
+func NewService(q *dbgen.Queries) *Service {
+	return &Service{queries: q}
+}
+
+// Result is what a progress event earned the user.
+type Result struct {
+	XPAwarded int     `json:"xp_awarded"`
+	Level     Level   `json:"level"`
+	NewBadges []Badge `json:"new_badges"`
+}
+
+// Award grants xp for a source the user just completed, then grants any
+// badges they now qualify for.
+func (s *Service) Award(ctx context.Context, userID uuid.UUID, source dbgen.XpSource, sourceID uuid.UUID, xp int32) (Result, error) {
+	rows, err := s.queries.AwardXP(ctx, dbgen.AwardXPParams{
+		UserID:   userID,
+		Source:   source,
+		SourceID: sourceID,
+		Xp:       xp,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	stats, err := s.stats(ctx, userID)
+	if err != nil {
+		return Result{}, err
+	}
+	badges, err := s.grantBadges(ctx, userID, stats)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Level: LevelFor(stats.XP), NewBadges: badges}
+	if rows > 0 {
+		res.XPAwarded = int(xp)
+	}
+	return res, nil
+}
+
+// Revoke takes back the XP for a source whose completion was undone. Badges
+// are kept.
+func (s *Service) Revoke(ctx context.Context, userID uuid.UUID, source dbgen.XpSource, sourceID uuid.UUID) error {
+	_, err := s.queries.RevokeXP(ctx, dbgen.RevokeXPParams{
+		UserID:   userID,
+		Source:   source,
+		SourceID: sourceID,
+	})
+	return err
+}
+
+func (s *Service) stats(ctx context.Context, userID uuid.UUID) (Stats, error) {
+	row, err := s.queries.GetBadgeStats(ctx, userID)
+	if err != nil {
+		return Stats{}, err
+	}
+	streak, err := s.currentStreak(ctx, userID)
+	if err != nil {
+		return Stats{}, err
+	}
+	return Stats{
+		LessonsCompleted:    int(row.LessonsCompleted),
+		SkillsCompleted:     int(row.SkillsCompleted),
+		ModulesCompleted:    int(row.ModulesCompleted),
+		ApprovedSubmissions: int(row.ApprovedSubmissions),
+		CurrentStreak:       streak,
+		XP:                  int(row.Xp),
+	}, nil
+}
+
+// currentStreak is the user's streak in their own time zone, as shown on the
+// activity calendar.
+func (s *Service) currentStreak(ctx context.Context, userID uuid.UUID) (int, error) {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	loc, err := activity.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	rows, err := s.queries.ListActivityByDay(ctx, dbgen.ListActivityByDayParams{
+		Timezone: loc.String(),
+		UserID:   userID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	active := make([]time.Time, len(rows))
+	for i, row := range rows {
+		active[i] = row.Day.Time
+	}
+	return activity.ComputeStreaks(active, activity.Date(time.Now().In(loc))).Current, nil
+}
+
+func (s *Service) grantBadges(ctx context.Context, userID uuid.UUID, stats Stats) ([]Badge, error) {
+	owned, err := s.queries.ListUserBadges(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ownedSet := make(map[string]bool, len(owned))
+	for _, b := range owned {
+		ownedSet[b.Badge] = true
+	}
+
+	granted := []Badge{}
+	for _, b := range NewlyEarned(stats, ownedSet) {
+		rows, err := s.queries.AddUserBadge(ctx, dbgen.AddUserBadgeParams{UserID: userID, Badge: b.ID})
+		if err != nil {
+			return nil, err
+		}
+		// A concurrent request may have granted it first.
+		if rows > 0 {
+			granted = append(granted, b)
+		}
+	}
+	return granted, nil
+}
+
+// EarnedBadge is a badge the user holds.
+type EarnedBadge struct {
+	Badge
+	EarnedAt time.Time `json:"earned_at"`
+}
+
+// Summary is the user's XP, level and badges.
+type Summary struct {
+	Level  Level         `json:"level"`
+	Badges []EarnedBadge `json:"badges"`
+}
+
+func (s *Service) Summary(ctx context.Context, userID uuid.UUID) (Summary, error) {
+	xp, err := s.queries.GetUserXP(ctx, userID)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	owned, err := s.queries.ListUserBadges(ctx, userID)
+	if err != nil {
+		return Summary{}, err
+	}
+	badges := make([]EarnedBadge, 0, len(owned))
+	for _, o := range owned {
+		// Badges removed from the catalogue are no longer shown.
+		if b, ok := BadgeByID(o.Badge); ok {
+			badges = append(badges, EarnedBadge{Badge: b, EarnedAt: o.EarnedAt.Time})
+		}
+	}
+
+	return Summary{Level: LevelFor(int(xp)), Badges: badges}, nil
+}
@@ -0,0 +1,50 @@
+// Package gamification awards XP for progress, derives levels from it and
+// grants achievement badges.
+package gamification
+
+// XP awards. Lessons are worth one XP per estimated minute, so longer lessons
+// count for more, with a floor so short lessons still feel worthwhile.
+const (
+	minLessonXP  = 10
+	SkillXP      = 25
+	SubmissionXP = 200
+)
+
+// xpPerLevelGap is how much the XP between consecutive levels grows.
+const xpPerLevelGap = 50
+
+// LessonXP is the XP for completing a lesson of the given length.
+func LessonXP(estimatedMinutes int32) int32 {
+	return max(minLessonXP, estimatedMinutes)
+}
+
+// Level describes where a total XP puts the user.
+type Level struct {
+	Level int `json:"level"`
+	XP    int `json:"xp"`
+	// LevelXP is the total XP at which the current level starts and NextLevelXP
+	// the total needed for the next one.
+	LevelXP     int `json:"level_xp"`
+	NextLevelXP int `json:"next_level_xp"`
+}
+
+// levelThreshold is the total XP needed to reach level n. The gap between
+// levels grows by xpPerLevelGap each level: 0, 100, 300, 600, 1000, ...
+func levelThreshold(n int) int {
+	return xpPerLevelGap * n * (n - 1)
+}
+
+// LevelFor returns the level reached with xp total XP. Everyone starts at
+// level 1.
+func LevelFor(xp int) Level {
+	n := 1
+	for levelThreshold(n+1) <= xp {
+		n++
+	}
+	return Level{
+		Level:       n,
+		XP:          xp,
+		LevelXP:     levelThreshold(n),
+		NextLevelXP: levelThreshold(n + 1),
+	}
+}
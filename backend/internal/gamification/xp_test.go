@@ -0,0 +1,61 @@
+package gamification
+
+import "testing"
+
+func TestLevelFor(t *testing.T) {
+	tests := []struct {
+		xp   int
+		want Level
+	}{
+		{0, Level{Level: 1, XP: 0, LevelXP: 0, NextLevelXP: 100}},
+		{99, Level{Level: 1, XP: 99, LevelXP: 0, NextLevelXP: 100}},
+		{100, Level{Level: 2, XP: 100, LevelXP: 100, NextLevelXP: 300}},
+		{299, Level{Level: 2, XP: 299, LevelXP: 100, NextLevelXP: 300}},
+		{300, Level{Level: 3, XP: 300, LevelXP: 300, NextLevelXP: 600}},
+		{1000, Level{Level: 5, XP: 1000, LevelXP: 1000, NextLevelXP: 1500}},
+	}
+	for _, tt := range tests {
+		if got := LevelFor(tt.xp); got != tt.want {
+			t.Errorf("LevelFor(%d) = %+v, want %+v", tt.xp, got, tt.want)
+		}
+	}
+}
+
+func TestLessonXP(t *testing.T) {
+	tests := []struct {
+		minutes int32
+		want    int32
+	}{
+		{0, minLessonXP},
+		{minLessonXP, minLessonXP},
+		{45, 45},
+	}
+	for _, tt := range tests {
+		if got := LessonXP(tt.minutes); got != tt.want {
+			t.Errorf("LessonXP(%d) = %d, want %d", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestNewlyEarned(t *testing.T) {
+	if got := NewlyEarned(Stats{}, nil); len(got) != 0 {
+		t.Errorf("NewlyEarned(no stats) = %v, want none", got)
+	}
+
+	s := Stats{LessonsCompleted: 1, CurrentStreak: 7, XP: 1000}
+	got := NewlyEarned(s, map[string]bool{"first-lesson": true})
+	var ids []string
+	for _, b := range got {
+		ids = append(ids, b.ID)
+	}
+	want := []string{"streak-7", "level-5"}
+	if len(ids) != len(want) {
+		t.Fatalf("NewlyEarned() = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("NewlyEarned() = %v, want %v", ids, want)
+			break
+		}
+	}
+}
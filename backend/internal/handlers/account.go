@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/activity"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/mailer"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+// accountResponse is the full profile returned by the /me endpoints.
+func accountResponse(user dbgen.User) map[string]any {
+	resp := userResponse(user)
+	resp["role"] = user.Role
+	resp["has_password"] = user.PasswordHash != ""
+	resp["two_factor_enabled"] = user.TotpEnabledAt.Valid
+	resp["timezone"] = user.Timezone
+	resp["created_at"] = user.CreatedAt
+	return resp
+}
+
+func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	respondOK(w, accountResponse(user))
+}
+
+type updateMeRequest struct {
+	Name     *string `json:"name"`
+	Timezone *string `json:"timezone"`
+}
+
+func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	var req updateMeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			respondError(w, http.StatusBadRequest, "name cannot be empty")
+			return
+		}
+
+		updated, err := h.queries.UpdateUserName(r.Context(), dbgen.UpdateUserNameParams{
+			ID:   user.ID,
+			Name: name,
+		})
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to update profile")
+			return
+		}
+		user = updated
+	}
+
+	// The time zone decides which calendar day activity counts towards.
+	if req.Timezone != nil {
+		loc, err := activity.LoadLocation(*req.Timezone)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "timezone must be an IANA time zone such as Europe/Berlin")
+			return
+		}
+		known, err := h.queries.IsKnownTimezone(r.Context(), loc.String())
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to verify timezone")
+			return
+		}
+		if !known {
+			respondError(w, http.StatusBadRequest, "timezone must be an IANA time zone such as Europe/Berlin")
+			return
+		}
+
+		updated, err := h.queries.UpdateUserTimezone(r.Context(), dbgen.UpdateUserTimezoneParams{
+			ID:       user.ID,
+			Timezone: loc.String(),
+		})
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to update profile")
+			return
+		}
+		user = updated
+	}
+
+	respondOK(w, accountResponse(user))
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// ChangePassword sets a new password after checking the current one and signs
+// out every other session. Accounts created through GitHub have no password
+// to check, and a stolen access token mustn't be enough to add one, so they
+// are emailed a reset link to set their first password instead.
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req changePasswordRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if user.PasswordHash == "" {
+		if !h.sendPasswordResetLink(w, r, user) {
+			return
+		}
+		respond(w, http.StatusAccepted, map[string]string{"status": "confirmation_sent"})
+		return
+	}
+
+	if len(req.NewPassword) < 8 {
+		respondError(w, http.StatusBadRequest, "password must be at least 8 characters")
+		return
+	}
+
+	if !auth.CheckPassword(req.CurrentPassword, user.PasswordHash) {
+		respondError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	hash, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to hash password")
+		return
+	}
+
+	if err := h.queries.UpdateUserPassword(r.Context(), dbgen.UpdateUserPasswordParams{
+		ID:           user.ID,
+		PasswordHash: hash,
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to update password")
+		return
+	}
+
+	if err := h.queries.InvalidateUserPasswordResetTokens(r.Context(), user.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to invalidate reset tokens")
+		return
+	}
+
+	// Keep the session making the request; every other one is revoked.
+	sessionID, _ := middleware.GetSessionID(r.Context())
+	if err := h.queries.RevokeOtherRefreshTokens(r.Context(), dbgen.RevokeOtherRefreshTokensParams{
+		UserID:   user.ID,
+		FamilyID: sessionID,
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	h.mailer.Send(mailer.EmailJob{
+		To:       user.Email,
+		Subject:  "Your Level Up Backend password was changed",
+		Template: "password_changed",
+		Data: map[string]string{
+			"name":      user.Name,
+			"reset_url": h.cfg.AppBaseURL + "/forgot-password",
+		},
+	})
+
+	respondOK(w, map[string]string{"status": "password_changed"})
+}
+
+type changeEmailRequest struct {
+	NewEmail string `json:"new_email"`
+	Password string `json:"password"`
+}
+
+// RequestEmailChange mails a confirmation link to the new address. The account
+// keeps its current email until the link is followed. Accounts without a
+// password have nothing to re-authenticate with, so the change is first
+// approved from the current address, which is what ApproveEmailChange handles.
+func (h *AuthHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req changeEmailRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	req.NewEmail = strings.ToLower(strings.TrimSpace(req.NewEmail))
+	if req.NewEmail == "" || !strings.Contains(req.NewEmail, "@") {
+		respondError(w, http.StatusBadRequest, "a valid new_email is required")
+		return
+	}
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if user.PasswordHash != "" && !auth.CheckPassword(req.Password, user.PasswordHash) {
+		respondError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	if req.NewEmail == user.Email {
+		respondError(w, http.StatusBadRequest, "new email is the same as the current email")
+		return
+	}
+
+	if _, err := h.queries.GetUserByEmail(r.Context(), req.NewEmail); err == nil {
+		respondError(w, http.StatusConflict, "email already registered")
+		return
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	if user.PasswordHash == "" {
+		token, err := h.auth.GenerateEmailChangeApprovalToken(user.ID, user.Email, req.NewEmail)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to generate approval token")
+			return
+		}
+
+		h.mailer.Send(mailer.EmailJob{
+			To:       user.Email,
+			Subject:  "Approve your Level Up Backend email change",
+			Template: "approve_email_change",
+			Data: map[string]string{
+				"name":        user.Name,
+				"new_email":   req.NewEmail,
+				"approve_url": h.cfg.AppBaseURL + "/approve-email-change?token=" + url.QueryEscape(token),
+				"expires_in":  h.cfg.EmailVerificationExpiry.String(),
+			},
+		})
+
+		respond(w, http.StatusAccepted, map[string]string{"status": "approval_sent"})
+		return
+	}
+
+	if !h.sendEmailChangeConfirmation(w, user, req.NewEmail) {
+		return
+	}
+	respond(w, http.StatusAccepted, map[string]string{"status": "confirmation_sent"})
+}
+
+// sendEmailChangeConfirmation mails the confirmation link to newEmail, writing
+// an error response on failure.
+func (h *AuthHandler) sendEmailChangeConfirmation(w http.ResponseWriter, user dbgen.User, newEmail string) bool {
+	token, err := h.auth.GenerateEmailChangeToken(user.ID, user.Email, newEmail)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate confirmation token")
+		return false
+	}
+
+	h.mailer.Send(mailer.EmailJob{
+		To:       newEmail,
+		Subject:  "Confirm your new Level Up Backend email",
+		Template: "confirm_email_change",
+		Data: map[string]string{
+			"name":        user.Name,
+			"confirm_url": h.cfg.AppBaseURL + "/confirm-email?token=" + url.QueryEscape(token),
+			"expires_in":  h.cfg.EmailVerificationExpiry.String(),
+		},
+	})
+	return true
+}
+
+type approveEmailChangeRequest struct {
+	Token string `json:"token"`
+}
+
+// ApproveEmailChange is the current address approving a change requested by
+// an account without a password. It then continues as RequestEmailChange
+// would, mailing the confirmation link to the new address.
+func (h *AuthHandler) ApproveEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req approveEmailChangeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	claims, err := h.auth.ParseToken(req.Token)
+	if err != nil || claims.TokenType != auth.TokenTypeEmailChangeApproval {
+		respondError(w, http.StatusBadRequest, "invalid or expired approval token")
+		return
+	}
+
+	user, err := h.queries.GetUserByID(r.Context(), claims.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusBadRequest, "invalid or expired approval token")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+	// The approval was for the address the account had when it was sent.
+	if user.Email != claims.Email {
+		respondError(w, http.StatusBadRequest, "invalid or expired approval token")
+		return
+	}
+
+	if !h.sendEmailChangeConfirmation(w, user, claims.NewEmail) {
+		return
+	}
+	respond(w, http.StatusAccepted, map[string]string{"status": "confirmation_sent"})
+}
+
+type confirmEmailChangeRequest struct {
+	Token string `json:"token"`
+}
+
+// ConfirmEmailChange switches the account to the new address and lets the old
+// address know it happened.
+func (h *AuthHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req confirmEmailChangeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	claims, err := h.auth.ParseToken(req.Token)
+	if err != nil || claims.TokenType != auth.TokenTypeEmailChange {
+		respondError(w, http.StatusBadRequest, "invalid or expired confirmation token")
+		return
+	}
+
+	// Matching on the old address makes the token single use: once the email
+	// has changed it no longer matches.
+	user, err := h.queries.ChangeUserEmail(r.Context(), dbgen.ChangeUserEmailParams{
+		ID:           claims.UserID,
+		CurrentEmail: claims.Email,
+		NewEmail:     claims.NewEmail,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusBadRequest, "invalid or expired confirmation token")
+			return
+		}
+		if strings.Contains(err.Error(), "unique") {
+			respondError(w, http.StatusConflict, "email already registered")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to change email")
+		return
+	}
+
+	h.mailer.Send(mailer.EmailJob{
+		To:       claims.Email,
+		Subject:  "Your Level Up Backend email was changed",
+		Template: "email_changed",
+		Data: map[string]string{
+			"name":      user.Name,
+			"new_email": user.Email,
+		},
+	})
+
+	respondOK(w, map[string]any{
+		"status": "email_changed",
+		"user":   userResponse(user),
+	})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/activity"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+// The calendar defaults to the last year, like a GitHub contribution graph,
+// and can't span more than about that.
+const (
+	activityDefaultDays = 365
+	activityMaxDays     = 366
+)
+
+type ActivityHandler struct {
+	queries *dbgen.Queries
+}
+
+func NewActivityHandler(q *dbgen.Queries) *ActivityHandler {
+	return &ActivityHandler{queries: q}
+}
+
+// GetActivity returns the user's daily activity between ?from= and ?to=
+// (YYYY-MM-DD, inclusive, in the user's time zone) and their streaks.
+func (h *ActivityHandler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	user, err := h.queries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+	loc, err := activity.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	today := activity.Date(time.Now().In(loc))
+
+	to := today
+	if s := r.URL.Query().Get("to"); s != "" {
+		if to, err = activity.ParseDate(s); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid to date")
+			return
+		}
+	}
+	from := to.AddDate(0, 0, -(activityDefaultDays - 1))
+	if s := r.URL.Query().Get("from"); s != "" {
+		if from, err = activity.ParseDate(s); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid from date")
+			return
+		}
+	}
+	if from.After(to) {
+		respondError(w, http.StatusBadRequest, "from must not be after to")
+		return
+	}
+	if to.Sub(from) >= activityMaxDays*24*time.Hour {
+		respondError(w, http.StatusBadRequest, "date range is too long")
+		return
+	}
+
+	rows, err := h.queries.ListActivityByDay(r.Context(), dbgen.ListActivityByDayParams{
+		Timezone: loc.String(),
+		UserID:   userID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get activity")
+		return
+	}
+
+	byDay := make(map[time.Time]activity.Counts, len(rows))
+	active := make([]time.Time, len(rows))
+	for i, row := range rows {
+		active[i] = row.Day.Time
+		byDay[row.Day.Time] = activity.Counts{
+			Lessons:     int(row.Lessons),
+			Skills:      int(row.Skills),
+			Submissions: int(row.Submissions),
+		}
+	}
+
+	respondOK(w, map[string]any{
+		"timezone": loc.String(),
+		"from":     from.Format(activity.DateLayout),
+		"to":       to.Format(activity.DateLayout),
+		"days":     activity.Calendar(byDay, from, to),
+		"streaks":  activity.ComputeStreaks(active, today),
+	})
+}
@@ -3,19 +3,23 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
 
 	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/gamification"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
 )
 
 type AdminHandler struct {
 	queries *dbgen.Queries
+	game    *gamification.Service
 }
 
-func NewAdminHandler(q *dbgen.Queries) *AdminHandler {
-	return &AdminHandler{queries: q}
+func NewAdminHandler(q *dbgen.Queries, game *gamification.Service) *AdminHandler {
+	return &AdminHandler{queries: q, game: game}
 }
 
 func (h *AdminHandler) ListSubmissions(w http.ResponseWriter, r *http.Request) {
@@ -68,5 +72,109 @@ func (h *AdminHandler) ReviewSubmission(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Approval earns the learner XP; moving an approved submission back to
+	// another status takes it away again.
+	if submission.Status == dbgen.SubmissionStatusApproved {
+		_, err = h.game.Award(r.Context(), submission.UserID, dbgen.XpSourceSubmission, submission.ID, gamification.SubmissionXP)
+	} else {
+		err = h.game.Revoke(r.Context(), submission.UserID, dbgen.XpSourceSubmission, submission.ID)
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to update xp")
+		return
+	}
+
 	respondOK(w, submission)
 }
+
+// UnlockUser clears a login lockout and the failed attempt counter.
+func (h *AdminHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if _, err := h.queries.GetUserByID(r.Context(), id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	if err := h.queries.ResetFailedLogins(r.Context(), id); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to unlock user")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "unlocked"})
+}
+
+// ListRoles returns every role with the permissions it grants.
+func (h *AdminHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.queries.ListRoles(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list roles")
+		return
+	}
+
+	respondOK(w, map[string]any{"roles": roles})
+}
+
+type setUserRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// SetUserRole assigns a role to a user and signs them out everywhere so the
+// new role applies from their next login.
+func (h *AdminHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	var req setUserRoleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Role == "" {
+		respondError(w, http.StatusBadRequest, "role is required")
+		return
+	}
+
+	if currentID, _ := middleware.GetUserID(r.Context()); currentID == id {
+		respondError(w, http.StatusBadRequest, "cannot change your own role")
+		return
+	}
+
+	user, err := h.queries.UpdateUserRole(r.Context(), dbgen.UpdateUserRoleParams{
+		ID:   id,
+		Role: req.Role,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		if strings.Contains(err.Error(), "foreign key") {
+			respondError(w, http.StatusBadRequest, "unknown role")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to update role")
+		return
+	}
+
+	if err := h.queries.RevokeUserRefreshTokens(r.Context(), user.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	respondOK(w, map[string]any{
+		"id":   user.ID,
+		"role": user.Role,
+	})
+}
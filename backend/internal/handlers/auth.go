@@ -5,21 +5,27 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 
-	"github.com/anujgupta/level-up-backend/internal/auth"
 	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/config"
 	"github.com/anujgupta/level-up-backend/internal/mailer"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
 )
 
 type AuthHandler struct {
+	pool    *pgxpool.Pool
 	queries *dbgen.Queries
+	cfg     *config.Config
 	auth    *auth.Service
 	mailer  *mailer.Mailer
 }
 
-func NewAuthHandler(q *dbgen.Queries, a *auth.Service, m *mailer.Mailer) *AuthHandler {
-	return &AuthHandler{queries: q, auth: a, mailer: m}
+func NewAuthHandler(pool *pgxpool.Pool, q *dbgen.Queries, cfg *config.Config, a *auth.Service, m *mailer.Mailer) *AuthHandler {
+	return &AuthHandler{pool: pool, queries: q, cfg: cfg, auth: a, mailer: m}
 }
 
 type registerRequest struct {
@@ -67,7 +73,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokens, err := h.auth.GenerateTokenPair(user.ID, user.Role)
+	tokens, err := issueTokens(r, h.queries, h.auth, user, uuid.New(), false)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to generate tokens")
 		return
@@ -79,16 +85,12 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Template: "welcome",
 		Data:     map[string]string{"name": user.Name},
 	})
+	h.sendVerificationEmail(user)
 
 	respondCreated(w, map[string]any{
 		"access_token":  tokens.AccessToken,
 		"refresh_token": tokens.RefreshToken,
-		"user": map[string]any{
-			"id":                  user.ID,
-			"email":               user.Email,
-			"name":                user.Name,
-			"subscription_status": user.SubscriptionStatus,
-		},
+		"user":          userResponse(user),
 	})
 }
 
@@ -116,27 +118,33 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !auth.CheckPassword(req.Password, user.PasswordHash) {
+	// A locked account gets the same 429 whether or not the password is right,
+	// so guesses made while locked reveal nothing. Wrong ones still count.
+	valid := auth.CheckPassword(req.Password, user.PasswordHash)
+	if !valid {
+		if err := h.recordFailedLogin(r.Context(), user); err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to record login attempt")
+			return
+		}
+	}
+	if respondIfLocked(w, user) {
+		return
+	}
+	if !valid {
 		respondError(w, http.StatusUnauthorized, "invalid credentials")
 		return
 	}
 
-	tokens, err := h.auth.GenerateTokenPair(user.ID, user.Role)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "failed to generate tokens")
-		return
+	// With 2FA on, the counter is only reset once the second factor passes;
+	// otherwise a known password would reset the lockout on every attempt.
+	if user.FailedLoginAttempts > 0 && !user.TotpEnabledAt.Valid {
+		if err := h.queries.ResetFailedLogins(r.Context(), user.ID); err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to reset login attempts")
+			return
+		}
 	}
 
-	respondOK(w, map[string]any{
-		"access_token":  tokens.AccessToken,
-		"refresh_token": tokens.RefreshToken,
-		"user": map[string]any{
-			"id":                  user.ID,
-			"email":               user.Email,
-			"name":                user.Name,
-			"subscription_status": user.SubscriptionStatus,
-		},
-	})
+	respondLogin(w, r, h.queries, h.auth, user, http.StatusOK)
 }
 
 type refreshRequest struct {
@@ -160,14 +168,44 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	stored, err := h.queries.GetRefreshTokenByHash(r.Context(), auth.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusUnauthorized, "invalid or expired refresh token")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch refresh token")
+		return
+	}
+	if stored.RevokedAt.Valid {
+		respondError(w, http.StatusUnauthorized, "refresh token revoked")
+		return
+	}
+
+	// A refresh token that was already rotated is being replayed: assume it was
+	// stolen and kill every token in its family.
+	rotated, err := h.queries.RotateRefreshToken(r.Context(), stored.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to rotate refresh token")
+		return
+	}
+	if rotated == 0 {
+		if err := h.queries.RevokeRefreshTokenFamily(r.Context(), stored.FamilyID); err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to revoke session")
+			return
+		}
+		respondError(w, http.StatusUnauthorized, "refresh token reuse detected")
+		return
+	}
+
 	// Fetch current role in case it changed
-	user, err := h.queries.GetUserByID(r.Context(), claims.UserID)
+	user, err := h.queries.GetUserByID(r.Context(), stored.UserID)
 	if err != nil {
 		respondError(w, http.StatusUnauthorized, "user not found")
 		return
 	}
 
-	tokens, err := h.auth.GenerateTokenPair(user.ID, user.Role)
+	tokens, err := issueTokens(r, h.queries, h.auth, user, stored.FamilyID, claims.MFA)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to generate tokens")
 		return
@@ -178,3 +216,54 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		"refresh_token": tokens.RefreshToken,
 	})
 }
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Logout revokes the session (refresh token family) the given refresh token
+// belongs to. Access tokens already issued stay valid until they expire.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req logoutRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.RefreshToken == "" {
+		respondError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	stored, err := h.queries.GetRefreshTokenByHash(r.Context(), auth.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusUnauthorized, "invalid refresh token")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch refresh token")
+		return
+	}
+
+	if err := h.queries.RevokeRefreshTokenFamily(r.Context(), stored.FamilyID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke session")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "logged_out"})
+}
+
+// LogoutAll revokes every refresh token the authenticated user holds.
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	if err := h.queries.RevokeUserRefreshTokens(r.Context(), userID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "logged_out"})
+}
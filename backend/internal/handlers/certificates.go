@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/certificate"
+	"github.com/anujgupta/level-up-backend/internal/config"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+type CertificatesHandler struct {
+	queries *dbgen.Queries
+	cfg     *config.Config
+}
+
+func NewCertificatesHandler(q *dbgen.Queries, cfg *config.Config) *CertificatesHandler {
+	return &CertificatesHandler{queries: q, cfg: cfg}
+}
+
+// certificateResponse is the owner's view of a certificate.
+func (h *CertificatesHandler) certificateResponse(c dbgen.Certificate) map[string]any {
+	return map[string]any{
+		"id":             c.ID,
+		"module_id":      c.ModuleID,
+		"recipient_name": c.RecipientName,
+		"module_title":   c.ModuleTitle,
+		"issued_at":      c.IssuedAt,
+		"verify_url":     h.verifyURL(c),
+		"pdf_url":        "/certificates/" + c.ID.String() + "/pdf",
+	}
+}
+
+// verifyURL is the public page that checks a certificate against the API.
+func (h *CertificatesHandler) verifyURL(c dbgen.Certificate) string {
+	return h.cfg.AppBaseURL + "/certificates/" + c.ID.String()
+}
+
+// moduleCompleted reports whether p covers every published lesson and skill
+// of a module that has lessons, and an approved assignment if it has one.
+func moduleCompleted(p moduleProgress) bool {
+	return p.TotalLessons > 0 &&
+		p.CompletedLessons == p.TotalLessons &&
+		p.CompletedSkills == p.TotalSkills &&
+		(p.AssignmentStatus == "none" || p.AssignmentStatus == string(dbgen.SubmissionStatusApproved))
+}
+
+// IssueCertificate issues the certificate for a module the user has finished.
+// Asking again returns the certificate already issued.
+func (h *CertificatesHandler) IssueCertificate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	module, err := moduleBySlug(r, h.queries, chi.URLParam(r, "slug"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "module not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to get module")
+		return
+	}
+
+	rows, err := h.queries.ListModuleProgress(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get progress")
+		return
+	}
+	var progress moduleProgress
+	for _, row := range rows {
+		if row.ModuleID == module.ID {
+			progress = newModuleProgress(row)
+			break
+		}
+	}
+	if !moduleCompleted(progress) {
+		respond(w, http.StatusForbidden, map[string]any{
+			"error":    "finish every lesson and skill and get the assignment approved first",
+			"code":     "module_not_completed",
+			"progress": progress,
+		})
+		return
+	}
+
+	user, err := h.queries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+
+	cert, err := h.queries.IssueCertificate(r.Context(), dbgen.IssueCertificateParams{
+		UserID:        userID,
+		ModuleID:      pgtype.UUID{Bytes: module.ID, Valid: true},
+		RecipientName: user.Name,
+		ModuleTitle:   module.Title,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to issue certificate")
+		return
+	}
+
+	respondOK(w, h.certificateResponse(cert))
+}
+
+func (h *CertificatesHandler) ListMyCertificates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	certs, err := h.queries.ListUserCertificates(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list certificates")
+		return
+	}
+
+	result := make([]map[string]any, len(certs))
+	for i, c := range certs {
+		result[i] = h.certificateResponse(c)
+	}
+	respondOK(w, map[string]any{"certificates": result})
+}
+
+// certificateByID loads the certificate named in the URL, writing a 404 for
+// unknown or malformed IDs.
+func (h *CertificatesHandler) certificateByID(w http.ResponseWriter, r *http.Request) (dbgen.Certificate, bool) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusNotFound, "certificate not found")
+		return dbgen.Certificate{}, false
+	}
+
+	cert, err := h.queries.GetCertificate(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "certificate not found")
+			return dbgen.Certificate{}, false
+		}
+		respondError(w, http.StatusInternalServerError, "failed to get certificate")
+		return dbgen.Certificate{}, false
+	}
+	return cert, true
+}
+
+// VerifyCertificate is public: anyone holding a certificate ID can check that
+// it is genuine. Only what's printed on the certificate is returned, never the
+// recipient's account details.
+func (h *CertificatesHandler) VerifyCertificate(w http.ResponseWriter, r *http.Request) {
+	cert, ok := h.certificateByID(w, r)
+	if !ok {
+		return
+	}
+
+	respondOK(w, map[string]any{
+		"id":             cert.ID,
+		"valid":          true,
+		"recipient_name": cert.RecipientName,
+		"module_title":   cert.ModuleTitle,
+		"issued_at":      cert.IssuedAt,
+	})
+}
+
+// CertificatePDF is public, like VerifyCertificate, so the PDF can be linked
+// from a résumé.
+func (h *CertificatesHandler) CertificatePDF(w http.ResponseWriter, r *http.Request) {
+	cert, ok := h.certificateByID(w, r)
+	if !ok {
+		return
+	}
+
+	pdf := certificate.PDF(certificate.Certificate{
+		ID:            cert.ID.String(),
+		RecipientName: cert.RecipientName,
+		ModuleTitle:   cert.ModuleTitle,
+		IssuedAt:      cert.IssuedAt.Time,
+		VerifyURL:     h.verifyURL(cert),
+	})
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="certificate-`+cert.ID.String()+`.pdf"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pdf)
+}
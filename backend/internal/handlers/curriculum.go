@@ -0,0 +1,731 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	appdb "github.com/anujgupta/level-up-backend/internal/db"
+)
+
+// CurriculumHandler serves the admin API for editing modules, lessons, skills
+// and assignments.
+type CurriculumHandler struct {
+	pool    *pgxpool.Pool
+	queries *dbgen.Queries
+}
+
+func NewCurriculumHandler(pool *pgxpool.Pool, q *dbgen.Queries) *CurriculumHandler {
+	return &CurriculumHandler{pool: pool, queries: q}
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+func validSlug(slug string) bool {
+	return len(slug) <= 100 && slugPattern.MatchString(slug)
+}
+
+const invalidSlugMessage = "slug must be lowercase letters, digits and single hyphens"
+
+func numericFromFloat(f float64) (pgtype.Numeric, error) {
+	var n pgtype.Numeric
+	err := n.Scan(strconv.FormatFloat(f, 'f', 1, 64))
+	return n, err
+}
+
+// ── Modules ──────────────────────────────────────────────────────────────────
+
+type moduleRequest struct {
+	Title          *string  `json:"title"`
+	Slug           *string  `json:"slug"`
+	Description    *string  `json:"description"`
+	EstimatedHours *float64 `json:"estimated_hours"`
+}
+
+// validate trims the fields and checks the ones that are set. When create is
+// true, title, slug and description are required.
+func (req *moduleRequest) validate(create bool) string {
+	trimPtr(req.Title)
+	trimPtr(req.Slug)
+	trimPtr(req.Description)
+
+	if create && (req.Title == nil || req.Slug == nil || req.Description == nil) {
+		return "title, slug, and description are required"
+	}
+	if req.Title != nil && *req.Title == "" {
+		return "title cannot be empty"
+	}
+	if req.Slug != nil && !validSlug(*req.Slug) {
+		return invalidSlugMessage
+	}
+	if req.EstimatedHours != nil && (*req.EstimatedHours < 0 || *req.EstimatedHours >= 1000) {
+		return "estimated_hours must be between 0 and 999.9"
+	}
+	return ""
+}
+
+func (h *CurriculumHandler) CreateModule(w http.ResponseWriter, r *http.Request) {
+	var req moduleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := req.validate(true); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	hours, err := numericFromFloat(derefOr(req.EstimatedHours, 0))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid estimated_hours")
+		return
+	}
+
+	module, err := h.queries.CreateModule(r.Context(), dbgen.CreateModuleParams{
+		Title:          *req.Title,
+		Slug:           *req.Slug,
+		Description:    *req.Description,
+		EstimatedHours: hours,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "unique") {
+			respondError(w, http.StatusConflict, "a module with this slug already exists")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to create module")
+		return
+	}
+
+	respondCreated(w, module)
+}
+
+func (h *CurriculumHandler) UpdateModule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	var req moduleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := req.validate(false); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	params := dbgen.UpdateModuleParams{
+		ID:          id,
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Description: req.Description,
+	}
+	if req.EstimatedHours != nil {
+		if params.EstimatedHours, err = numericFromFloat(*req.EstimatedHours); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid estimated_hours")
+			return
+		}
+	}
+
+	module, err := h.queries.UpdateModule(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "module not found")
+			return
+		}
+		if strings.Contains(err.Error(), "unique") {
+			respondError(w, http.StatusConflict, "a module with this slug already exists")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to update module")
+		return
+	}
+
+	respondOK(w, module)
+}
+
+// DeleteModule removes a module with its lessons, skills and assignment.
+// Modules learners have made progress in or submitted work for cannot be
+// deleted; archive them instead.
+func (h *CurriculumHandler) DeleteModule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	if !h.ensureNoSubmissions(w, r, id) {
+		return
+	}
+
+	// Deleting would cascade away learners' progress; archiving keeps it.
+	progress, err := h.queries.CountModuleProgress(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to count progress")
+		return
+	}
+	if progress > 0 {
+		respondError(w, http.StatusConflict, "module has learner progress and cannot be deleted; archive it instead")
+		return
+	}
+
+	deleted, err := h.queries.DeleteModule(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete module")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "module not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CurriculumHandler) ReorderModules(w http.ResponseWriter, r *http.Request) {
+	ids, ok := decodeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.reorder(r.Context(), ids,
+		func(q *dbgen.Queries) ([]uuid.UUID, error) {
+			modules, err := q.ListModules(r.Context())
+			return collectIDs(modules, func(m dbgen.Module) uuid.UUID { return m.ID }), err
+		},
+		func(q *dbgen.Queries, id uuid.UUID, index int32) error {
+			return q.SetModuleOrderIndex(r.Context(), dbgen.SetModuleOrderIndexParams{ID: id, OrderIndex: index})
+		},
+	)
+	respondReorder(w, err)
+}
+
+// ── Lessons ──────────────────────────────────────────────────────────────────
+
+type lessonRequest struct {
+	Title            *string `json:"title"`
+	Slug             *string `json:"slug"`
+	Content          *string `json:"content"`
+	EstimatedMinutes *int32  `json:"estimated_minutes"`
+	IsPreview        *bool   `json:"is_preview"`
+}
+
+func (req *lessonRequest) validate(create bool) string {
+	trimPtr(req.Title)
+	trimPtr(req.Slug)
+
+	if create && (req.Title == nil || req.Slug == nil) {
+		return "title and slug are required"
+	}
+	if req.Title != nil && *req.Title == "" {
+		return "title cannot be empty"
+	}
+	if req.Slug != nil && !validSlug(*req.Slug) {
+		return invalidSlugMessage
+	}
+	if req.EstimatedMinutes != nil && *req.EstimatedMinutes < 0 {
+		return "estimated_minutes cannot be negative"
+	}
+	return ""
+}
+
+func (h *CurriculumHandler) CreateLesson(w http.ResponseWriter, r *http.Request) {
+	moduleID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	var req lessonRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := req.validate(true); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// New lessons start as drafts with their first revision.
+	var lesson dbgen.Lesson
+	err = appdb.WithTx(r.Context(), h.pool, func(tx pgx.Tx) error {
+		q := h.queries.WithTx(tx)
+
+		var err error
+		lesson, err = q.CreateLesson(r.Context(), dbgen.CreateLessonParams{
+			ModuleID:         moduleID,
+			Title:            *req.Title,
+			Slug:             *req.Slug,
+			Content:          derefOr(req.Content, ""),
+			EstimatedMinutes: derefOr(req.EstimatedMinutes, 0),
+			IsPreview:        derefOr(req.IsPreview, false),
+		})
+		if err != nil {
+			return err
+		}
+		return h.saveRevision(r, q, lesson)
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			respondError(w, http.StatusNotFound, "module not found")
+			return
+		}
+		if strings.Contains(err.Error(), "unique") {
+			respondError(w, http.StatusConflict, "a lesson with this slug already exists in the module")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to create lesson")
+		return
+	}
+
+	respondCreated(w, lesson)
+}
+
+func (h *CurriculumHandler) UpdateLesson(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return
+	}
+
+	var req lessonRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if msg := req.validate(false); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// Edits change the working copy only; learners keep seeing the published
+	// revision. Every title or content change is kept as a new revision.
+	var lesson dbgen.Lesson
+	err = appdb.WithTx(r.Context(), h.pool, func(tx pgx.Tx) error {
+		q := h.queries.WithTx(tx)
+
+		var err error
+		lesson, err = q.UpdateLesson(r.Context(), dbgen.UpdateLessonParams{
+			ID:               id,
+			Title:            req.Title,
+			Slug:             req.Slug,
+			Content:          req.Content,
+			EstimatedMinutes: req.EstimatedMinutes,
+			IsPreview:        req.IsPreview,
+		})
+		if err != nil {
+			return err
+		}
+		if req.Title == nil && req.Content == nil {
+			return nil
+		}
+		return h.saveRevision(r, q, lesson)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "lesson not found")
+			return
+		}
+		if strings.Contains(err.Error(), "unique") {
+			respondError(w, http.StatusConflict, "a lesson with this slug already exists in the module")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to update lesson")
+		return
+	}
+
+	respondOK(w, lesson)
+}
+
+func (h *CurriculumHandler) DeleteLesson(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return
+	}
+
+	// Deleting would cascade away learners' progress; archiving keeps it.
+	count, err := h.queries.CountLessonProgress(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to count progress")
+		return
+	}
+	if count > 0 {
+		respondError(w, http.StatusConflict, "lesson has learner progress and cannot be deleted; archive it instead")
+		return
+	}
+
+	deleted, err := h.queries.DeleteLesson(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete lesson")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "lesson not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CurriculumHandler) ReorderLessons(w http.ResponseWriter, r *http.Request) {
+	moduleID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	ids, ok := decodeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	err = h.reorder(r.Context(), ids,
+		func(q *dbgen.Queries) ([]uuid.UUID, error) {
+			lessons, err := q.GetLessonsByModule(r.Context(), moduleID)
+			return collectIDs(lessons, func(l dbgen.Lesson) uuid.UUID { return l.ID }), err
+		},
+		func(q *dbgen.Queries, id uuid.UUID, index int32) error {
+			return q.SetLessonOrderIndex(r.Context(), dbgen.SetLessonOrderIndexParams{ID: id, OrderIndex: index})
+		},
+	)
+	respondReorder(w, err)
+}
+
+// ── Skills ───────────────────────────────────────────────────────────────────
+
+type skillRequest struct {
+	SkillName string `json:"skill_name"`
+}
+
+func (h *CurriculumHandler) CreateSkill(w http.ResponseWriter, r *http.Request) {
+	moduleID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	var req skillRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.SkillName = strings.TrimSpace(req.SkillName)
+	if req.SkillName == "" {
+		respondError(w, http.StatusBadRequest, "skill_name is required")
+		return
+	}
+
+	skill, err := h.queries.CreateSkill(r.Context(), dbgen.CreateSkillParams{
+		ModuleID:  moduleID,
+		SkillName: req.SkillName,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			respondError(w, http.StatusNotFound, "module not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to create skill")
+		return
+	}
+
+	respondCreated(w, skill)
+}
+
+func (h *CurriculumHandler) UpdateSkill(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid skill id")
+		return
+	}
+
+	var req skillRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.SkillName = strings.TrimSpace(req.SkillName)
+	if req.SkillName == "" {
+		respondError(w, http.StatusBadRequest, "skill_name is required")
+		return
+	}
+
+	skill, err := h.queries.UpdateSkill(r.Context(), dbgen.UpdateSkillParams{
+		ID:        id,
+		SkillName: req.SkillName,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "skill not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to update skill")
+		return
+	}
+
+	respondOK(w, skill)
+}
+
+func (h *CurriculumHandler) DeleteSkill(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid skill id")
+		return
+	}
+
+	count, err := h.queries.CountSkillProgress(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to count progress")
+		return
+	}
+	if count > 0 {
+		respondError(w, http.StatusConflict, "skill has learner progress and cannot be deleted")
+		return
+	}
+
+	deleted, err := h.queries.DeleteSkill(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete skill")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "skill not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CurriculumHandler) ReorderSkills(w http.ResponseWriter, r *http.Request) {
+	moduleID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	ids, ok := decodeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	err = h.reorder(r.Context(), ids,
+		func(q *dbgen.Queries) ([]uuid.UUID, error) {
+			skills, err := q.GetSkillsByModule(r.Context(), moduleID)
+			return collectIDs(skills, func(s dbgen.Skill) uuid.UUID { return s.ID }), err
+		},
+		func(q *dbgen.Queries, id uuid.UUID, index int32) error {
+			return q.SetSkillOrderIndex(r.Context(), dbgen.SetSkillOrderIndexParams{ID: id, OrderIndex: index})
+		},
+	)
+	respondReorder(w, err)
+}
+
+// ── Assignments ──────────────────────────────────────────────────────────────
+
+type assignmentRequest struct {
+	Title          string  `json:"title"`
+	Description    string  `json:"description"`
+	Rubric         string  `json:"rubric"`
+	EstimatedHours float64 `json:"estimated_hours"`
+}
+
+// PutAssignment creates or replaces the module's assignment. Each module has
+// at most one.
+func (h *CurriculumHandler) PutAssignment(w http.ResponseWriter, r *http.Request) {
+	moduleID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	var req assignmentRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Title == "" || req.Description == "" {
+		respondError(w, http.StatusBadRequest, "title and description are required")
+		return
+	}
+	if req.EstimatedHours < 0 || req.EstimatedHours >= 1000 {
+		respondError(w, http.StatusBadRequest, "estimated_hours must be between 0 and 999.9")
+		return
+	}
+
+	hours, err := numericFromFloat(req.EstimatedHours)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid estimated_hours")
+		return
+	}
+
+	assignment, err := h.queries.UpsertAssignment(r.Context(), dbgen.UpsertAssignmentParams{
+		ModuleID:       moduleID,
+		Title:          req.Title,
+		Description:    req.Description,
+		Rubric:         req.Rubric,
+		EstimatedHours: hours,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			respondError(w, http.StatusNotFound, "module not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to save assignment")
+		return
+	}
+
+	respondOK(w, assignment)
+}
+
+func (h *CurriculumHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	moduleID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	if !h.ensureNoSubmissions(w, r, moduleID) {
+		return
+	}
+
+	deleted, err := h.queries.DeleteAssignmentByModuleID(r.Context(), moduleID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete assignment")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "assignment not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ── Helpers ──────────────────────────────────────────────────────────────────
+
+// ensureNoSubmissions refuses to delete curriculum learners have submitted
+// work against, since the submissions would be cascaded away with it.
+func (h *CurriculumHandler) ensureNoSubmissions(w http.ResponseWriter, r *http.Request, moduleID uuid.UUID) bool {
+	count, err := h.queries.CountModuleSubmissions(r.Context(), moduleID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to count submissions")
+		return false
+	}
+	if count > 0 {
+		respondError(w, http.StatusConflict, "module has learner submissions and cannot be deleted")
+		return false
+	}
+	return true
+}
+
+type reorderRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func decodeOrder(w http.ResponseWriter, r *http.Request) ([]uuid.UUID, bool) {
+	var req reorderRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return nil, false
+	}
+
+	ids := make([]uuid.UUID, len(req.IDs))
+	for i, s := range req.IDs {
+		id, err := parseUUID(s)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid id in ids")
+			return nil, false
+		}
+		ids[i] = id
+	}
+	return ids, true
+}
+
+var errOrderMismatch = errors.New("ids must list every item exactly once")
+
+// reorder sets order_index to 1..n following ids inside a single transaction.
+// ids must be a permutation of the IDs returned by current.
+func (h *CurriculumHandler) reorder(
+	ctx context.Context,
+	ids []uuid.UUID,
+	current func(q *dbgen.Queries) ([]uuid.UUID, error),
+	set func(q *dbgen.Queries, id uuid.UUID, index int32) error,
+) error {
+	return appdb.WithTx(ctx, h.pool, func(tx pgx.Tx) error {
+		q := h.queries.WithTx(tx)
+
+		existing, err := current(q)
+		if err != nil {
+			return err
+		}
+		if len(existing) != len(ids) {
+			return errOrderMismatch
+		}
+
+		remaining := make(map[uuid.UUID]bool, len(existing))
+		for _, id := range existing {
+			remaining[id] = true
+		}
+		for _, id := range ids {
+			if !remaining[id] {
+				return errOrderMismatch
+			}
+			delete(remaining, id)
+		}
+
+		for i, id := range ids {
+			if err := set(q, id, int32(i+1)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func respondReorder(w http.ResponseWriter, err error) {
+	if err != nil {
+		if errors.Is(err, errOrderMismatch) {
+			respondError(w, http.StatusBadRequest, errOrderMismatch.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to reorder")
+		return
+	}
+	respondOK(w, map[string]string{"status": "reordered"})
+}
+
+func collectIDs[T any](items []T, id func(T) uuid.UUID) []uuid.UUID {
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = id(item)
+	}
+	return ids
+}
+
+func trimPtr(s *string) {
+	if s != nil {
+		*s = strings.TrimSpace(*s)
+	}
+}
+
+func derefOr[T any](p *T, fallback T) T {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/jackc/pgx/v5"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/mailer"
+)
+
+// sendVerificationEmail mails a signed verification link for the user's
+// current address. Failures are dropped; the user can request a resend.
+func (h *AuthHandler) sendVerificationEmail(user dbgen.User) {
+	token, err := h.auth.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return
+	}
+
+	h.mailer.Send(mailer.EmailJob{
+		To:       user.Email,
+		Subject:  "Verify your Level Up Backend email",
+		Template: "verify_email",
+		Data: map[string]string{
+			"name":       user.Name,
+			"verify_url": h.cfg.AppBaseURL + "/verify-email?token=" + url.QueryEscape(token),
+			"expires_in": h.cfg.EmailVerificationExpiry.String(),
+		},
+	})
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req verifyEmailRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	claims, err := h.auth.ParseToken(req.Token)
+	if err != nil || claims.TokenType != auth.TokenTypeEmailVerify {
+		respondError(w, http.StatusBadRequest, "invalid or expired verification token")
+		return
+	}
+
+	user, err := h.queries.GetUserByID(r.Context(), claims.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusBadRequest, "invalid or expired verification token")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	if user.Email != claims.Email {
+		respondError(w, http.StatusBadRequest, "invalid or expired verification token")
+		return
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		respondOK(w, map[string]string{"status": "already_verified"})
+		return
+	}
+
+	if _, err := h.queries.MarkUserEmailVerified(r.Context(), dbgen.MarkUserEmailVerifiedParams{
+		ID:    user.ID,
+		Email: user.Email,
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to verify email")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "verified"})
+}
+
+func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		respondError(w, http.StatusConflict, "email already verified")
+		return
+	}
+
+	h.sendVerificationEmail(user)
+
+	respond(w, http.StatusAccepted, map[string]string{"status": "verification_sent"})
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/config"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+type ImpersonationHandler struct {
+	queries *dbgen.Queries
+	cfg     *config.Config
+	auth    *auth.Service
+}
+
+func NewImpersonationHandler(q *dbgen.Queries, cfg *config.Config, a *auth.Service) *ImpersonationHandler {
+	return &ImpersonationHandler{queries: q, cfg: cfg, auth: a}
+}
+
+type impersonateRequest struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// Impersonate issues a short-lived access token that lets the admin see the
+// API exactly as the target user does. Every session is written to the
+// impersonation audit table before the token is handed out.
+func (h *ImpersonationHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	var req impersonateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	targetID, err := parseUUID(req.UserID)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		respondError(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+
+	if targetID == adminID {
+		respondError(w, http.StatusBadRequest, "cannot impersonate yourself")
+		return
+	}
+
+	target, err := h.queries.GetUserByID(r.Context(), targetID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	// Staff accounts are off limits: impersonating one would hand out its
+	// permissions.
+	if target.Role != auth.RoleUser {
+		respondError(w, http.StatusForbidden, "only learner accounts can be impersonated")
+		return
+	}
+
+	expiresAt := time.Now().Add(h.cfg.ImpersonationExpiry)
+	session, err := h.queries.CreateImpersonationSession(r.Context(), dbgen.CreateImpersonationSessionParams{
+		AdminID:      pgtype.UUID{Bytes: adminID, Valid: true},
+		TargetUserID: pgtype.UUID{Bytes: target.ID, Valid: true},
+		Reason:       req.Reason,
+		IpAddress:    r.RemoteAddr,
+		UserAgent:    r.UserAgent(),
+		ExpiresAt:    pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to record impersonation session")
+		return
+	}
+
+	token, err := h.auth.GenerateImpersonationToken(target.ID, target.Role, adminID, session.ID, expiresAt)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	respondCreated(w, map[string]any{
+		"access_token": token,
+		"session_id":   session.ID,
+		"expires_at":   session.ExpiresAt,
+		"user":         userResponse(target),
+	})
+}
+
+// EndImpersonation closes an impersonation session early; its token stops
+// working on the next request.
+func (h *ImpersonationHandler) EndImpersonation(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	ended, err := h.queries.EndImpersonationSession(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to end impersonation session")
+		return
+	}
+	if ended == 0 {
+		respondError(w, http.StatusNotFound, "active impersonation session not found")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "ended"})
+}
+
+const impersonationPageSize = 50
+
+// ListImpersonations returns the impersonation audit trail, newest first.
+func (h *ImpersonationHandler) ListImpersonations(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	sessions, err := h.queries.ListImpersonationSessions(r.Context(), dbgen.ListImpersonationSessionsParams{
+		Limit:  impersonationPageSize,
+		Offset: int32(offset),
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list impersonation sessions")
+		return
+	}
+
+	respondOK(w, map[string]any{"sessions": sessions})
+}
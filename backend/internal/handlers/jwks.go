@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/anujgupta/level-up-backend/internal/auth"
+)
+
+type JWKSHandler struct {
+	auth *auth.Service
+}
+
+func NewJWKSHandler(a *auth.Service) *JWKSHandler {
+	return &JWKSHandler{auth: a}
+}
+
+// GetJWKS publishes the public keys other services use to verify our tokens.
+func (h *JWKSHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondOK(w, map[string]any{"keys": h.auth.JWKS()})
+}
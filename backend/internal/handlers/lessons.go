@@ -3,27 +3,45 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 
 	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/gamification"
+	"github.com/anujgupta/level-up-backend/internal/markdown"
 	"github.com/anujgupta/level-up-backend/internal/middleware"
 )
 
+// renderCacheSize bounds how many rendered lesson revisions are kept in memory.
+const renderCacheSize = 512
+
 type LessonsHandler struct {
-	queries *dbgen.Queries
+	queries  *dbgen.Queries
+	game     *gamification.Service
+	rendered *markdown.Cache
 }
 
-func NewLessonsHandler(q *dbgen.Queries) *LessonsHandler {
-	return &LessonsHandler{queries: q}
+func NewLessonsHandler(q *dbgen.Queries, game *gamification.Service) *LessonsHandler {
+	return &LessonsHandler{queries: q, game: game, rendered: markdown.NewCache(renderCacheSize)}
 }
 
+// GetLesson returns a lesson as raw Markdown, or with ?format=html as
+// sanitized HTML along with its table of contents and reading time. Free users
+// can only read preview lessons.
 func (h *LessonsHandler) GetLesson(w http.ResponseWriter, r *http.Request) {
 	moduleSlug := chi.URLParam(r, "slug")
 	lessonSlug := chi.URLParam(r, "lessonSlug")
 
-	module, err := h.queries.GetModuleBySlug(r.Context(), moduleSlug)
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "markdown" && format != "html" {
+		respondError(w, http.StatusBadRequest, "invalid format: must be markdown or html")
+		return
+	}
+
+	module, err := moduleBySlug(r, h.queries, moduleSlug)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			respondError(w, http.StatusNotFound, "module not found")
@@ -33,7 +51,53 @@ func (h *LessonsHandler) GetLesson(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	lesson, err := h.queries.GetLessonBySlug(r.Context(), dbgen.GetLessonBySlugParams{
+	// Editors see the working copy, including unpublished edits.
+	editor := canEditContent(r, h.queries)
+	if editor {
+		lesson, err := h.queries.GetLessonBySlug(r.Context(), dbgen.GetLessonBySlugParams{
+			ModuleID: module.ID,
+			Slug:     lessonSlug,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				respondError(w, http.StatusNotFound, "lesson not found")
+				return
+			}
+			respondError(w, http.StatusInternalServerError, "failed to get lesson")
+			return
+		}
+
+		body := map[string]any{
+			"id":                    lesson.ID,
+			"module_id":             lesson.ModuleID,
+			"title":                 lesson.Title,
+			"slug":                  lesson.Slug,
+			"content":               lesson.Content,
+			"order_index":           lesson.OrderIndex,
+			"estimated_minutes":     lesson.EstimatedMinutes,
+			"is_preview":            lesson.IsPreview,
+			"status":                lesson.Status,
+			"publish_at":            lesson.PublishAt,
+			"published_revision_id": lesson.PublishedRevisionID,
+		}
+		if format == "html" {
+			// Every save writes a revision, so the working copy is the latest
+			// one. Render the revision itself so the cache key always matches
+			// the content cached under it.
+			latest, err := h.queries.GetLatestLessonRevision(r.Context(), lesson.ID)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "failed to fetch latest revision")
+				return
+			}
+			if !h.addRendered(w, body, latest.ID, latest.Content) {
+				return
+			}
+		}
+		respondOK(w, body)
+		return
+	}
+
+	lesson, err := h.queries.GetPublishedLessonBySlug(r.Context(), dbgen.GetPublishedLessonBySlugParams{
 		ModuleID: module.ID,
 		Slug:     lessonSlug,
 	})
@@ -46,7 +110,24 @@ func (h *LessonsHandler) GetLesson(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondOK(w, map[string]any{
+	// Preview lessons don't need a subscription, but like every lesson they
+	// stay locked until their prerequisites are done.
+	if !lesson.IsPreview {
+		fullAccess, err := canReadPaidContent(r, h.queries)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to verify subscription")
+			return
+		}
+		if !fullAccess {
+			respondUpgradeRequired(w)
+			return
+		}
+	}
+	if !requireLessonUnlocked(w, r, h.queries, editor, module.ID, lesson.ID) {
+		return
+	}
+
+	body := map[string]any{
 		"id":                lesson.ID,
 		"module_id":         lesson.ModuleID,
 		"title":             lesson.Title,
@@ -54,7 +135,157 @@ func (h *LessonsHandler) GetLesson(w http.ResponseWriter, r *http.Request) {
 		"content":           lesson.Content,
 		"order_index":       lesson.OrderIndex,
 		"estimated_minutes": lesson.EstimatedMinutes,
+		"is_preview":        lesson.IsPreview,
+	}
+	if format == "html" && !h.addRendered(w, body, lesson.RevisionID, lesson.Content) {
+		return
+	}
+
+	// Serving the lesson counts as a view. It only feeds "continue where you
+	// left off", so a failure here shouldn't fail the request.
+	if canRecordProgress(r) {
+		userID, _ := middleware.GetUserID(r.Context())
+		_, _ = h.queries.RecordLessonView(r.Context(), dbgen.RecordLessonViewParams{
+			UserID:   userID,
+			LessonID: lesson.ID,
+		})
+	}
+
+	respondOK(w, body)
+}
+
+// UncompleteLesson undoes CompleteLesson. It succeeds whether or not the lesson
+// was completed, so retries are harmless.
+func (h *LessonsHandler) UncompleteLesson(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	lessonID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return
+	}
+
+	if _, err := h.queries.UnmarkLessonComplete(r.Context(), dbgen.UnmarkLessonCompleteParams{
+		UserID:   userID,
+		LessonID: lessonID,
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to mark lesson incomplete")
+		return
+	}
+	if err := h.game.Revoke(r.Context(), userID, dbgen.XpSourceLesson, lessonID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke xp")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "not_completed"})
+}
+
+type lessonViewRequest struct {
+	ScrollPosition *float64 `json:"scroll_position"`
+	Section        *string  `json:"section"`
+}
+
+const maxSectionLen = 200
+
+// RecordView is the reading heartbeat: it stores how far through the lesson
+// the user has scrolled and which section (TOC anchor) they're on.
+func (h *LessonsHandler) RecordView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	lessonID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return
+	}
+
+	var req lessonViewRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if p := req.ScrollPosition; p != nil && (*p < 0 || *p > 1) {
+		respondError(w, http.StatusBadRequest, "scroll_position must be between 0 and 1")
+		return
+	}
+	if req.Section != nil && len(*req.Section) > maxSectionLen {
+		respondError(w, http.StatusBadRequest, "section is too long")
+		return
+	}
+
+	// Learners can only be reading lessons that GetLesson would serve them.
+	if !canEditContent(r, h.queries) {
+		published, err := h.queries.IsLessonPublished(r.Context(), lessonID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to verify lesson")
+			return
+		}
+		if !published {
+			respondError(w, http.StatusNotFound, "lesson not found")
+			return
+		}
+
+		lesson, err := h.queries.GetLessonByID(r.Context(), lessonID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to verify lesson")
+			return
+		}
+		if !lesson.IsPreview {
+			fullAccess, err := canReadPaidContent(r, h.queries)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "failed to verify subscription")
+				return
+			}
+			if !fullAccess {
+				respondUpgradeRequired(w)
+				return
+			}
+		}
+		if !requireLessonUnlocked(w, r, h.queries, false, lesson.ModuleID, lesson.ID) {
+			return
+		}
+	}
+
+	view, err := h.queries.RecordLessonView(r.Context(), dbgen.RecordLessonViewParams{
+		UserID:         userID,
+		LessonID:       lessonID,
+		ScrollPosition: req.ScrollPosition,
+		Section:        req.Section,
 	})
+	if err != nil {
+		if strings.Contains(err.Error(), "foreign key") {
+			respondError(w, http.StatusNotFound, "lesson not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to record view")
+		return
+	}
+
+	respondOK(w, view)
+}
+
+// addRendered replaces the Markdown content in body with the rendered
+// revision, writing an error response on failure.
+func (h *LessonsHandler) addRendered(w http.ResponseWriter, body map[string]any, revisionID uuid.UUID, content string) bool {
+	doc, err := h.rendered.Render(revisionID, content)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to render lesson")
+		return false
+	}
+
+	delete(body, "content")
+	body["revision_id"] = revisionID
+	body["html"] = doc.HTML
+	body["toc"] = doc.TOC
+	body["reading_minutes"] = doc.ReadingMinutes
+	return true
 }
 
 func (h *LessonsHandler) CompleteLesson(w http.ResponseWriter, r *http.Request) {
@@ -71,14 +302,37 @@ func (h *LessonsHandler) CompleteLesson(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Verify lesson exists
-	if _, err := h.queries.GetLessonByID(r.Context(), lessonID); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+	// Verify lesson exists and, for learners, is published
+	var lesson dbgen.Lesson
+	editor := canEditContent(r, h.queries)
+	if editor {
+		if lesson, err = h.queries.GetLessonByID(r.Context(), lessonID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				respondError(w, http.StatusNotFound, "lesson not found")
+				return
+			}
+			respondError(w, http.StatusInternalServerError, "failed to verify lesson")
+			return
+		}
+	} else {
+		published, err := h.queries.IsLessonPublished(r.Context(), lessonID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to verify lesson")
+			return
+		}
+		if !published {
 			respondError(w, http.StatusNotFound, "lesson not found")
 			return
 		}
-		respondError(w, http.StatusInternalServerError, "failed to verify lesson")
-		return
+
+		lesson, err = h.queries.GetLessonByID(r.Context(), lessonID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to verify lesson")
+			return
+		}
+		if !requireLessonUnlocked(w, r, h.queries, editor, lesson.ModuleID, lesson.ID) {
+			return
+		}
 	}
 
 	if err := h.queries.MarkLessonComplete(r.Context(), dbgen.MarkLessonCompleteParams{
@@ -89,5 +343,12 @@ func (h *LessonsHandler) CompleteLesson(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	respondOK(w, map[string]string{"status": "completed"})
+	// Awards are idempotent, so if this fails a retry picks it up.
+	reward, err := h.game.Award(r.Context(), userID, dbgen.XpSourceLesson, lessonID, gamification.LessonXP(lesson.EstimatedMinutes))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to award xp")
+		return
+	}
+
+	respondOK(w, map[string]any{"status": "completed", "reward": reward})
 }
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/mailer"
+)
+
+// respondIfLocked writes a 429 with Retry-After and returns true if the
+// account is currently locked out.
+func respondIfLocked(w http.ResponseWriter, user dbgen.User) bool {
+	if !isLocked(user) {
+		return false
+	}
+
+	remaining := time.Until(user.LockedUntil.Time)
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(remaining.Seconds()))))
+	respondError(w, http.StatusTooManyRequests, "account temporarily locked due to failed login attempts")
+	return true
+}
+
+func isLocked(user dbgen.User) bool {
+	return user.LockedUntil.Valid && time.Until(user.LockedUntil.Time) > 0
+}
+
+// recordFailedLogin bumps the user's failed attempt counter, restarting it if
+// the last failure is older than LoginAttemptWindow, and once it reaches the
+// configured threshold, locks the account. Every further failure
+// doubles the lockout, up to LoginLockoutMax.
+func (h *AuthHandler) recordFailedLogin(ctx context.Context, user dbgen.User) error {
+	attempts, err := h.queries.RecordFailedLogin(ctx, dbgen.RecordFailedLoginParams{
+		ID:          user.ID,
+		WindowStart: pgtype.Timestamptz{Time: time.Now().Add(-h.cfg.LoginAttemptWindow), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if int(attempts) < h.cfg.LoginMaxAttempts {
+		return nil
+	}
+
+	lockout := lockoutDuration(int(attempts)-h.cfg.LoginMaxAttempts, h.cfg.LoginLockoutBase, h.cfg.LoginLockoutMax)
+	lockedUntil := time.Now().Add(lockout)
+
+	if err := h.queries.LockUser(ctx, dbgen.LockUserParams{
+		ID:          user.ID,
+		LockedUntil: pgtype.Timestamptz{Time: lockedUntil, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	// Only tell the user when the account becomes locked, not on every
+	// further failure, so failed attempts can't be used to flood their inbox.
+	if isLocked(user) {
+		return nil
+	}
+	h.mailer.Send(mailer.EmailJob{
+		To:       user.Email,
+		Subject:  "Your Level Up Backend account has been locked",
+		Template: "account_locked",
+		Data: map[string]string{
+			"name":         user.Name,
+			"locked_until": lockedUntil.UTC().Format(time.RFC1123),
+			"reset_url":    h.cfg.AppBaseURL + "/forgot-password",
+		},
+	})
+
+	return nil
+}
+
+// lockoutDuration returns base * 2^n, capped at max.
+func lockoutDuration(n int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 0; i < n; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	return min(d, max)
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	appdb "github.com/anujgupta/level-up-backend/internal/db"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+const recoveryCodeCount = 10
+
+// SetupMFA generates a new TOTP secret for the user. 2FA is not active until
+// the user proves their authenticator works via EnableMFA.
+func (h *AuthHandler) SetupMFA(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		respondError(w, http.StatusConflict, "two-factor authentication is already enabled")
+		return
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate totp secret")
+		return
+	}
+
+	if err := h.queries.SetUserTOTPSecret(r.Context(), dbgen.SetUserTOTPSecretParams{
+		ID:         user.ID,
+		TotpSecret: &secret,
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to store totp secret")
+		return
+	}
+
+	respondOK(w, map[string]string{
+		"secret":           secret,
+		"provisioning_uri": auth.TOTPProvisioningURI(secret, user.Email),
+	})
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// EnableMFA confirms the pending TOTP secret and returns one-time recovery codes.
+func (h *AuthHandler) EnableMFA(w http.ResponseWriter, r *http.Request) {
+	var req mfaCodeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		respondError(w, http.StatusConflict, "two-factor authentication is already enabled")
+		return
+	}
+	if user.TotpSecret == nil {
+		respondError(w, http.StatusBadRequest, "call /auth/2fa/setup first")
+		return
+	}
+
+	valid, err := h.checkTOTP(r.Context(), user, req.Code)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to verify code")
+		return
+	}
+	if !valid {
+		respondError(w, http.StatusUnauthorized, "invalid two-factor code")
+		return
+	}
+
+	var codes []string
+	err = appdb.WithTx(r.Context(), h.pool, func(tx pgx.Tx) error {
+		q := h.queries.WithTx(tx)
+		if _, err := q.EnableUserTOTP(r.Context(), user.ID); err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(r.Context(), q, user.ID)
+		return err
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to enable two-factor authentication")
+		return
+	}
+
+	// Sessions signed in before 2FA was turned on never passed it; keep only
+	// the one making the request.
+	sessionID, _ := middleware.GetSessionID(r.Context())
+	if err := h.queries.RevokeOtherRefreshTokens(r.Context(), dbgen.RevokeOtherRefreshTokensParams{
+		UserID:   user.ID,
+		FamilyID: sessionID,
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	respondOK(w, map[string]any{
+		"status":         "enabled",
+		"recovery_codes": codes,
+	})
+}
+
+type disableMFARequest struct {
+	Password     string `json:"password"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+func (h *AuthHandler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	var req disableMFARequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if !user.TotpEnabledAt.Valid {
+		respondError(w, http.StatusConflict, "two-factor authentication is not enabled")
+		return
+	}
+
+	if user.PasswordHash != "" && !auth.CheckPassword(req.Password, user.PasswordHash) {
+		respondError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	valid, err := h.checkSecondFactor(r.Context(), user, req.Code, req.RecoveryCode)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to verify code")
+		return
+	}
+	if !valid {
+		respondError(w, http.StatusUnauthorized, "invalid two-factor code")
+		return
+	}
+
+	if err := h.queries.DisableUserTOTP(r.Context(), user.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to disable two-factor authentication")
+		return
+	}
+	if err := h.queries.DeleteUserRecoveryCodes(r.Context(), user.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete recovery codes")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "disabled"})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after a valid TOTP code.
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var req mfaCodeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	if !user.TotpEnabledAt.Valid {
+		respondError(w, http.StatusConflict, "two-factor authentication is not enabled")
+		return
+	}
+
+	valid, err := h.checkTOTP(r.Context(), user, req.Code)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to verify code")
+		return
+	}
+	if !valid {
+		respondError(w, http.StatusUnauthorized, "invalid two-factor code")
+		return
+	}
+
+	var codes []string
+	err = appdb.WithTx(r.Context(), h.pool, func(tx pgx.Tx) error {
+		codes, err = replaceRecoveryCodes(r.Context(), h.queries.WithTx(tx), user.ID)
+		return err
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate recovery codes")
+		return
+	}
+
+	respondOK(w, map[string]any{"recovery_codes": codes})
+}
+
+type mfaLoginRequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// CompleteMFALogin is the second step of login for users with 2FA enabled.
+// It exchanges the MFA challenge token plus a TOTP or recovery code for a session.
+func (h *AuthHandler) CompleteMFALogin(w http.ResponseWriter, r *http.Request) {
+	var req mfaLoginRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	claims, err := h.auth.ParseToken(req.MFAToken)
+	if err != nil || claims.TokenType != auth.TokenTypeMFA {
+		respondError(w, http.StatusUnauthorized, "invalid or expired mfa token")
+		return
+	}
+
+	user, err := h.queries.GetUserByID(r.Context(), claims.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusUnauthorized, "user not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	if !user.TotpEnabledAt.Valid {
+		respondError(w, http.StatusBadRequest, "two-factor authentication is not enabled")
+		return
+	}
+
+	if respondIfLocked(w, user) {
+		return
+	}
+
+	valid, err := h.checkSecondFactor(r.Context(), user, req.Code, req.RecoveryCode)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to verify code")
+		return
+	}
+	if !valid {
+		// Wrong codes count towards the same lockout as wrong passwords so the
+		// six-digit code cannot be brute forced with a valid MFA token.
+		if err := h.recordFailedLogin(r.Context(), user); err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to record login attempt")
+			return
+		}
+		respondError(w, http.StatusUnauthorized, "invalid two-factor code")
+		return
+	}
+
+	if user.FailedLoginAttempts > 0 {
+		if err := h.queries.ResetFailedLogins(r.Context(), user.ID); err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to reset login attempts")
+			return
+		}
+	}
+
+	tokens, err := issueTokens(r, h.queries, h.auth, user, uuid.New(), true)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate tokens")
+		return
+	}
+
+	respondOK(w, map[string]any{
+		"access_token":  tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"user":          userResponse(user),
+	})
+}
+
+// currentUser loads the authenticated user, writing an error response on failure.
+func (h *AuthHandler) currentUser(w http.ResponseWriter, r *http.Request) (dbgen.User, bool) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return dbgen.User{}, false
+	}
+
+	user, err := h.queries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "user not found")
+			return dbgen.User{}, false
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return dbgen.User{}, false
+	}
+	return user, true
+}
+
+// checkSecondFactor accepts either a TOTP code or an unused recovery code.
+func (h *AuthHandler) checkSecondFactor(ctx context.Context, user dbgen.User, code, recoveryCode string) (bool, error) {
+	if recoveryCode != "" {
+		used, err := h.queries.UseRecoveryCode(ctx, dbgen.UseRecoveryCodeParams{
+			UserID:   user.ID,
+			CodeHash: auth.HashToken(auth.NormalizeRecoveryCode(recoveryCode)),
+		})
+		if err != nil {
+			return false, err
+		}
+		return used == 1, nil
+	}
+	return h.checkTOTP(ctx, user, code)
+}
+
+// checkTOTP validates a TOTP code and records its time step so the same code
+// cannot be replayed within its validity window.
+func (h *AuthHandler) checkTOTP(ctx context.Context, user dbgen.User, code string) (bool, error) {
+	if user.TotpSecret == nil {
+		return false, nil
+	}
+
+	step, ok := auth.ValidateTOTP(*user.TotpSecret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	recorded, err := h.queries.RecordTOTPStep(ctx, dbgen.RecordTOTPStepParams{
+		ID:           user.ID,
+		TotpLastStep: &step,
+	})
+	if err != nil {
+		return false, err
+	}
+	return recorded == 1, nil
+}
+
+// replaceRecoveryCodes swaps the user's recovery codes for a fresh set. Run it
+// in a transaction so a failure part way leaves the old codes in place.
+func replaceRecoveryCodes(ctx context.Context, q *dbgen.Queries, userID uuid.UUID) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := q.DeleteUserRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+	for _, code := range codes {
+		if err := q.CreateRecoveryCode(ctx, dbgen.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: auth.HashToken(code),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
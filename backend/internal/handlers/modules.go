@@ -5,36 +5,53 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	appdb "github.com/anujgupta/level-up-backend/internal/db"
 	"github.com/anujgupta/level-up-backend/internal/middleware"
 )
 
 type ModulesHandler struct {
+	pool    *pgxpool.Pool
 	queries *dbgen.Queries
 }
 
-func NewModulesHandler(q *dbgen.Queries) *ModulesHandler {
-	return &ModulesHandler{queries: q}
+func NewModulesHandler(pool *pgxpool.Pool, q *dbgen.Queries) *ModulesHandler {
+	return &ModulesHandler{pool: pool, queries: q}
 }
 
 func (h *ModulesHandler) ListModules(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r.Context())
 
-	modules, err := h.queries.ListModules(r.Context())
+	editor := canEditContent(r, h.queries)
+	fullAccess, err := canReadPaidContent(r, h.queries)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to verify subscription")
+		return
+	}
+
+	var modules []dbgen.Module
+	if editor {
+		modules, err = h.queries.ListModules(r.Context())
+	} else {
+		modules, err = h.queries.ListPublishedModules(r.Context())
+	}
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to list modules")
 		return
 	}
 
-	completedIDs, err := h.queries.GetCompletedLessonIDs(r.Context(), userID)
+	progressRows, err := h.queries.ListModuleProgress(r.Context(), userID)
 	if err != nil {
-		completedIDs = nil
+		respondError(w, http.StatusInternalServerError, "failed to get progress")
+		return
 	}
-	completedSet := make(map[string]bool, len(completedIDs))
-	for _, id := range completedIDs {
-		completedSet[id.String()] = true
+	progress := make(map[uuid.UUID]moduleProgress, len(progressRows))
+	for _, p := range progressRows {
+		progress[p.ModuleID] = newModuleProgress(p)
 	}
 
 	type moduleItem struct {
@@ -44,6 +61,45 @@ func (h *ModulesHandler) ListModules(w http.ResponseWriter, r *http.Request) {
 		Description    string  `json:"description"`
 		OrderIndex     int32   `json:"order_index"`
 		EstimatedHours float64 `json:"estimated_hours"`
+		Status         string  `json:"status,omitempty"`
+
+		Progress moduleProgress `json:"progress"`
+
+		// Free users can read a module's preview lessons; everything else
+		// requires an upgrade.
+		RequiresUpgrade bool        `json:"requires_upgrade"`
+		PreviewLessons  []lessonRef `json:"preview_lessons"`
+
+		Locked               bool           `json:"locked"`
+		LockedReason         string         `json:"locked_reason,omitempty"`
+		MissingPrerequisites []prerequisite `json:"missing_prerequisites,omitempty"`
+	}
+
+	// Editors are never locked out, so they don't need the lookup.
+	missing := make(map[uuid.UUID][]prerequisite)
+	if !editor {
+		unmet, err := h.queries.ListUnmetModulePrerequisites(r.Context(), userID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to check prerequisites")
+			return
+		}
+		for _, u := range unmet {
+			missing[u.ModuleID] = append(missing[u.ModuleID], modulePrerequisite(u.RequiredModuleID, u.RequiredModuleSlug, u.RequiredModuleTitle))
+		}
+	}
+
+	previews, err := h.queries.ListPreviewLessons(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list preview lessons")
+		return
+	}
+	previewsByModule := make(map[uuid.UUID][]lessonRef)
+	for _, p := range previews {
+		previewsByModule[p.ModuleID] = append(previewsByModule[p.ModuleID], lessonRef{
+			ID:    p.ID.String(),
+			Slug:  p.Slug,
+			Title: p.Title,
+		})
 	}
 
 	result := make([]moduleItem, len(modules))
@@ -56,17 +112,82 @@ func (h *ModulesHandler) ListModules(w http.ResponseWriter, r *http.Request) {
 			Description:    m.Description,
 			OrderIndex:     m.OrderIndex,
 			EstimatedHours: hours.Float64,
+
+			Progress: progress[m.ID],
+
+			RequiresUpgrade: !fullAccess,
+			PreviewLessons:  previewsByModule[m.ID],
+		}
+		if result[i].PreviewLessons == nil {
+			result[i].PreviewLessons = []lessonRef{}
+		}
+		if editor {
+			result[i].Status = string(m.Status)
+		}
+		if prereqs := missing[m.ID]; len(prereqs) > 0 {
+			result[i].Locked = true
+			result[i].LockedReason = lockedReason(prereqs)
+			result[i].MissingPrerequisites = prereqs
 		}
 	}
 
 	respondOK(w, map[string]any{"modules": result})
 }
 
+// moduleProgress is the caller's progress through one module. An approved
+// submission counts as one more completed item towards the percentage.
+type moduleProgress struct {
+	TotalLessons     int32      `json:"total_lessons"`
+	CompletedLessons int32      `json:"completed_lessons"`
+	TotalSkills      int32      `json:"total_skills"`
+	CompletedSkills  int32      `json:"completed_skills"`
+	AssignmentStatus string     `json:"assignment_status"`
+	PercentComplete  int        `json:"percent_complete"`
+	NextLesson       *lessonRef `json:"next_lesson"`
+}
+
+func newModuleProgress(row dbgen.ListModuleProgressRow) moduleProgress {
+	p := moduleProgress{
+		TotalLessons:     row.TotalLessons,
+		CompletedLessons: row.CompletedLessons,
+		TotalSkills:      row.TotalSkills,
+		CompletedSkills:  row.CompletedSkills,
+	}
+
+	total := int(row.TotalLessons + row.TotalSkills)
+	done := int(row.CompletedLessons + row.CompletedSkills)
+	switch {
+	case !row.HasAssignment:
+		p.AssignmentStatus = "none"
+	case !row.SubmissionStatus.Valid:
+		p.AssignmentStatus = "not_submitted"
+		total++
+	default:
+		p.AssignmentStatus = string(row.SubmissionStatus.SubmissionStatus)
+		total++
+		if row.SubmissionStatus.SubmissionStatus == dbgen.SubmissionStatusApproved {
+			done++
+		}
+	}
+	if total > 0 {
+		p.PercentComplete = done * 100 / total
+	}
+
+	if row.NextLessonID.Valid {
+		p.NextLesson = &lessonRef{
+			ID:    uuid.UUID(row.NextLessonID.Bytes).String(),
+			Slug:  derefOr(row.NextLessonSlug, ""),
+			Title: derefOr(row.NextLessonTitle, ""),
+		}
+	}
+	return p
+}
+
 func (h *ModulesHandler) GetModule(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 	userID, _ := middleware.GetUserID(r.Context())
 
-	module, err := h.queries.GetModuleBySlug(r.Context(), slug)
+	module, err := moduleBySlug(r, h.queries, slug)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			respondError(w, http.StatusNotFound, "module not found")
@@ -76,9 +197,14 @@ func (h *ModulesHandler) GetModule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	lessons, err := h.queries.GetLessonsByModule(r.Context(), module.ID)
+	editor := canEditContent(r, h.queries)
+	if !requireModuleUnlocked(w, r, h.queries, editor, module.ID) {
+		return
+	}
+
+	fullAccess, err := canReadPaidContent(r, h.queries)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "failed to get lessons")
+		respondError(w, http.StatusInternalServerError, "failed to verify subscription")
 		return
 	}
 
@@ -96,16 +222,49 @@ func (h *ModulesHandler) GetModule(w http.ResponseWriter, r *http.Request) {
 		Slug             string `json:"slug"`
 		OrderIndex       int32  `json:"order_index"`
 		EstimatedMinutes int32  `json:"estimated_minutes"`
+		IsPreview        bool   `json:"is_preview"`
+		RequiresUpgrade  bool   `json:"requires_upgrade"`
+		Status           string `json:"status,omitempty"`
 	}
 
-	lessonList := make([]lessonItem, len(lessons))
-	for i, l := range lessons {
-		lessonList[i] = lessonItem{
-			ID:               l.ID.String(),
-			Title:            l.Title,
-			Slug:             l.Slug,
-			OrderIndex:       l.OrderIndex,
-			EstimatedMinutes: l.EstimatedMinutes,
+	// Editors see every lesson with its working title; learners only see
+	// published lessons, titled as published.
+	var lessonList []lessonItem
+	if editor {
+		lessons, err := h.queries.GetLessonsByModule(r.Context(), module.ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to get lessons")
+			return
+		}
+		lessonList = make([]lessonItem, len(lessons))
+		for i, l := range lessons {
+			lessonList[i] = lessonItem{
+				ID:               l.ID.String(),
+				Title:            l.Title,
+				Slug:             l.Slug,
+				OrderIndex:       l.OrderIndex,
+				EstimatedMinutes: l.EstimatedMinutes,
+				IsPreview:        l.IsPreview,
+				Status:           string(l.Status),
+			}
+		}
+	} else {
+		lessons, err := h.queries.GetPublishedLessonsByModule(r.Context(), module.ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to get lessons")
+			return
+		}
+		lessonList = make([]lessonItem, len(lessons))
+		for i, l := range lessons {
+			lessonList[i] = lessonItem{
+				ID:               l.ID.String(),
+				Title:            l.Title,
+				Slug:             l.Slug,
+				OrderIndex:       l.OrderIndex,
+				EstimatedMinutes: l.EstimatedMinutes,
+				IsPreview:        l.IsPreview,
+				RequiresUpgrade:  !fullAccess && !l.IsPreview,
+			}
 		}
 	}
 
@@ -116,9 +275,58 @@ func (h *ModulesHandler) GetModule(w http.ResponseWriter, r *http.Request) {
 		"slug":             module.Slug,
 		"description":      module.Description,
 		"order_index":      module.OrderIndex,
+		"requires_upgrade": !fullAccess,
 		"estimated_hours":  hours.Float64,
-		"total_lessons":    len(lessons),
+		"total_lessons":    len(lessonList),
 		"completed_lessons": completedCount,
 		"lessons":          lessonList,
 	})
 }
+
+// ResetModule clears the user's lesson and skill progress in a module so they
+// can start it over. The cleared completions are archived, not lost.
+// Submissions are kept.
+func (h *ModulesHandler) ResetModule(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	module, err := moduleBySlug(r, h.queries, chi.URLParam(r, "slug"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "module not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to get module")
+		return
+	}
+
+	var lessonsReset, skillsReset int64
+	err = appdb.WithTx(r.Context(), h.pool, func(tx pgx.Tx) error {
+		q := h.queries.WithTx(tx)
+
+		if lessonsReset, err = q.ResetModuleLessonProgress(r.Context(), dbgen.ResetModuleLessonProgressParams{
+			UserID:   userID,
+			ModuleID: module.ID,
+		}); err != nil {
+			return err
+		}
+		skillsReset, err = q.ResetModuleSkillProgress(r.Context(), dbgen.ResetModuleSkillProgressParams{
+			UserID:   userID,
+			ModuleID: module.ID,
+		})
+		return err
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to reset module progress")
+		return
+	}
+
+	respondOK(w, map[string]any{
+		"status":        "reset",
+		"lessons_reset": lessonsReset,
+		"skills_reset":  skillsReset,
+	})
+}
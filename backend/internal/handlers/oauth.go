@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/mailer"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+	"github.com/anujgupta/level-up-backend/internal/oauth"
+)
+
+type OAuthHandler struct {
+	queries *dbgen.Queries
+	auth    *auth.Service
+	github  *oauth.GitHub
+	mailer  *mailer.Mailer
+	logger  *slog.Logger
+}
+
+func NewOAuthHandler(q *dbgen.Queries, a *auth.Service, gh *oauth.GitHub, m *mailer.Mailer, logger *slog.Logger) *OAuthHandler {
+	return &OAuthHandler{queries: q, auth: a, github: gh, mailer: m, logger: logger}
+}
+
+// GitHubLogin returns the GitHub consent URL for signing in. The client must
+// keep the returned state and compare it with the one GitHub redirects back with.
+func (h *OAuthHandler) GitHubLogin(w http.ResponseWriter, r *http.Request) {
+	h.respondAuthURL(w, uuid.Nil)
+}
+
+// GitHubLink returns a consent URL that links GitHub to the authenticated user.
+func (h *OAuthHandler) GitHubLink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+	h.respondAuthURL(w, userID)
+}
+
+func (h *OAuthHandler) respondAuthURL(w http.ResponseWriter, linkUserID uuid.UUID) {
+	state, err := h.auth.GenerateOAuthState(linkUserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate oauth state")
+		return
+	}
+
+	respondOK(w, map[string]string{
+		"url":   h.github.AuthCodeURL(state),
+		"state": state,
+	})
+}
+
+type githubCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+// GitHubCallback completes the authorization-code flow. Depending on the state
+// it either links GitHub to an existing user or signs the user in, creating or
+// linking an account by verified email as needed.
+func (h *OAuthHandler) GitHubCallback(w http.ResponseWriter, r *http.Request) {
+	var req githubCallbackRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Code == "" || req.State == "" {
+		respondError(w, http.StatusBadRequest, "code and state are required")
+		return
+	}
+
+	state, err := h.auth.ParseToken(req.State)
+	if err != nil || state.TokenType != auth.TokenTypeOAuthState {
+		respondError(w, http.StatusBadRequest, "invalid or expired oauth state")
+		return
+	}
+
+	accessToken, err := h.github.Exchange(r.Context(), req.Code)
+	if err != nil {
+		h.logger.Warn("github code exchange failed", "err", err)
+		respondError(w, http.StatusBadGateway, "failed to exchange github code")
+		return
+	}
+
+	ghUser, err := h.github.FetchUser(r.Context(), accessToken)
+	if err != nil {
+		h.logger.Warn("github user fetch failed", "err", err)
+		respondError(w, http.StatusBadGateway, "failed to fetch github profile")
+		return
+	}
+
+	if state.UserID != uuid.Nil {
+		h.linkGitHub(w, r, state.UserID, ghUser)
+		return
+	}
+
+	user, created, err := h.findOrCreateGitHubUser(r, ghUser)
+	if err != nil {
+		if errors.Is(err, errGitHubEmailRequired) {
+			respondError(w, http.StatusBadRequest, "github account has no verified primary email")
+			return
+		}
+		if errors.Is(err, errGitHubEmailUnverified) {
+			respondError(w, http.StatusConflict, "an account with this email already exists; sign in to it and link GitHub from your account settings")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to sign in with github")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		h.mailer.Send(mailer.EmailJob{
+			To:       user.Email,
+			Subject:  "Welcome to Level Up Backend",
+			Template: "welcome",
+			Data:     map[string]string{"name": user.Name},
+		})
+	}
+
+	respondLogin(w, r, h.queries, h.auth, user, status)
+}
+
+var (
+	errGitHubEmailRequired   = errors.New("github email required")
+	errGitHubEmailUnverified = errors.New("existing account email is not verified")
+)
+
+func (h *OAuthHandler) findOrCreateGitHubUser(r *http.Request, ghUser *oauth.GitHubUser) (dbgen.User, bool, error) {
+	user, err := h.queries.GetUserByGitHubID(r.Context(), &ghUser.ID)
+	if err == nil {
+		// Keep the stored login current; GitHub users can rename themselves.
+		if user.GithubLogin == nil || *user.GithubLogin != ghUser.Login {
+			user, err = h.queries.LinkUserGitHub(r.Context(), dbgen.LinkUserGitHubParams{
+				ID:          user.ID,
+				GithubID:    &ghUser.ID,
+				GithubLogin: &ghUser.Login,
+			})
+		}
+		return user, false, err
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return dbgen.User{}, false, err
+	}
+
+	if ghUser.Email == "" {
+		return dbgen.User{}, false, errGitHubEmailRequired
+	}
+
+	// GitHub has verified this address, so an existing account with the same
+	// email belongs to the same person, but only if we verified it too.
+	// Otherwise someone could have registered the address first, set a
+	// password, and would share the account once it's linked.
+	user, err = h.queries.GetUserByEmail(r.Context(), ghUser.Email)
+	if err == nil {
+		if !user.EmailVerifiedAt.Valid {
+			return dbgen.User{}, false, errGitHubEmailUnverified
+		}
+		user, err = h.queries.LinkUserGitHub(r.Context(), dbgen.LinkUserGitHubParams{
+			ID:          user.ID,
+			GithubID:    &ghUser.ID,
+			GithubLogin: &ghUser.Login,
+		})
+		return user, false, err
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return dbgen.User{}, false, err
+	}
+
+	name := strings.TrimSpace(ghUser.Name)
+	if name == "" {
+		name = ghUser.Login
+	}
+
+	user, err = h.queries.CreateGitHubUser(r.Context(), dbgen.CreateGitHubUserParams{
+		Email:       ghUser.Email,
+		Name:        name,
+		GithubID:    &ghUser.ID,
+		GithubLogin: &ghUser.Login,
+	})
+	if err != nil {
+		return dbgen.User{}, false, err
+	}
+	return user, true, nil
+}
+
+func (h *OAuthHandler) linkGitHub(w http.ResponseWriter, r *http.Request, userID uuid.UUID, ghUser *oauth.GitHubUser) {
+	existing, err := h.queries.GetUserByGitHubID(r.Context(), &ghUser.ID)
+	if err == nil && existing.ID != userID {
+		respondError(w, http.StatusConflict, "github account is already linked to another user")
+		return
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	user, err := h.queries.LinkUserGitHub(r.Context(), dbgen.LinkUserGitHubParams{
+		ID:          userID,
+		GithubID:    &ghUser.ID,
+		GithubLogin: &ghUser.Login,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to link github account")
+		return
+	}
+
+	respondOK(w, map[string]any{
+		"status":       "linked",
+		"github_login": user.GithubLogin,
+	})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/mailer"
+)
+
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ForgotPassword emails a single-use reset link. The response is identical
+// whether or not the email is registered so it cannot be used to probe accounts.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req forgotPasswordRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if req.Email == "" {
+		respondError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	accepted := map[string]string{
+		"status": "if that email is registered, a reset link has been sent",
+	}
+
+	user, err := h.queries.GetUserByEmail(r.Context(), req.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respond(w, http.StatusAccepted, accepted)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	if !h.sendPasswordResetLink(w, r, user) {
+		return
+	}
+
+	respond(w, http.StatusAccepted, accepted)
+}
+
+// sendPasswordResetLink creates a single-use reset token and mails the link to
+// the user, writing an error response on failure.
+func (h *AuthHandler) sendPasswordResetLink(w http.ResponseWriter, r *http.Request, user dbgen.User) bool {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate reset token")
+		return false
+	}
+
+	if _, err := h.queries.CreatePasswordResetToken(r.Context(), dbgen.CreatePasswordResetTokenParams{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(h.cfg.PasswordResetExpiry), Valid: true},
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to create reset token")
+		return false
+	}
+
+	h.mailer.Send(mailer.EmailJob{
+		To:       user.Email,
+		Subject:  "Reset your Level Up Backend password",
+		Template: "password_reset",
+		Data: map[string]string{
+			"name":       user.Name,
+			"reset_url":  h.cfg.AppBaseURL + "/reset-password?token=" + url.QueryEscape(token),
+			"expires_in": h.cfg.PasswordResetExpiry.String(),
+		},
+	})
+	return true
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ResetPassword consumes a reset token, sets the new password and revokes every
+// existing session for the user.
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Token == "" || req.Password == "" {
+		respondError(w, http.StatusBadRequest, "token and password are required")
+		return
+	}
+	if len(req.Password) < 8 {
+		respondError(w, http.StatusBadRequest, "password must be at least 8 characters")
+		return
+	}
+
+	resetToken, err := h.queries.GetPasswordResetTokenByHash(r.Context(), auth.HashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusBadRequest, "invalid or expired reset token")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch reset token")
+		return
+	}
+
+	// Marking the token used is the guard against double use: only one request
+	// can flip used_at, and expired tokens never match.
+	used, err := h.queries.UsePasswordResetToken(r.Context(), resetToken.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to consume reset token")
+		return
+	}
+	if used == 0 {
+		respondError(w, http.StatusBadRequest, "invalid or expired reset token")
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to hash password")
+		return
+	}
+
+	if err := h.queries.UpdateUserPassword(r.Context(), dbgen.UpdateUserPasswordParams{
+		ID:           resetToken.UserID,
+		PasswordHash: hash,
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to update password")
+		return
+	}
+
+	// The lockout email points here, so a reset also lifts the lockout.
+	if err := h.queries.ResetFailedLogins(r.Context(), resetToken.UserID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to reset login attempts")
+		return
+	}
+
+	if err := h.queries.InvalidateUserPasswordResetTokens(r.Context(), resetToken.UserID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to invalidate reset tokens")
+		return
+	}
+
+	if err := h.queries.RevokeUserRefreshTokens(r.Context(), resetToken.UserID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "password_reset"})
+}
@@ -48,6 +48,11 @@ func (h *PaymentsHandler) CreateCheckoutSession(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	if !user.EmailVerifiedAt.Valid {
+		respondError(w, http.StatusForbidden, "email address must be verified before checkout")
+		return
+	}
+
 	if user.SubscriptionStatus == dbgen.SubscriptionStatusActive {
 		respondError(w, http.StatusConflict, "user already has an active subscription")
 		return
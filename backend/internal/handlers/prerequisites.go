@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	appdb "github.com/anujgupta/level-up-backend/internal/db"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+// prerequisite is content the user has to complete before something else
+// unlocks.
+type prerequisite struct {
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	ModuleSlug string `json:"module_slug"`
+	LessonSlug string `json:"lesson_slug,omitempty"`
+	Title      string `json:"title"`
+}
+
+func modulePrerequisite(id uuid.UUID, slug, title string) prerequisite {
+	return prerequisite{Type: "module", ID: id.String(), ModuleSlug: slug, Title: title}
+}
+
+// lockedReason describes what is still missing, e.g.
+// "complete Go Concurrency and Distributed Systems first".
+func lockedReason(missing []prerequisite) string {
+	titles := make([]string, len(missing))
+	for i, p := range missing {
+		titles[i] = p.Title
+	}
+
+	list := titles[0]
+	if n := len(titles); n > 1 {
+		list = strings.Join(titles[:n-1], ", ") + " and " + titles[n-1]
+	}
+	return "complete " + list + " first"
+}
+
+// respondLocked writes the 403 for content whose prerequisites aren't done.
+func respondLocked(w http.ResponseWriter, msg string, missing []prerequisite) {
+	respond(w, http.StatusForbidden, map[string]any{
+		"error":         msg,
+		"code":          "prerequisites_not_met",
+		"reason":        lockedReason(missing),
+		"prerequisites": missing,
+	})
+}
+
+// requireModuleUnlocked writes an error response and returns false unless the
+// caller has completed every prerequisite of the module. Editors, as reported
+// by canEditContent, are never locked out.
+func requireModuleUnlocked(w http.ResponseWriter, r *http.Request, q *dbgen.Queries, editor bool, moduleID uuid.UUID) bool {
+	if editor {
+		return true
+	}
+	userID, _ := middleware.GetUserID(r.Context())
+
+	rows, err := q.GetUnmetModulePrerequisites(r.Context(), dbgen.GetUnmetModulePrerequisitesParams{
+		ModuleID: moduleID,
+		UserID:   userID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to check prerequisites")
+		return false
+	}
+	if len(rows) == 0 {
+		return true
+	}
+
+	missing := make([]prerequisite, len(rows))
+	for i, row := range rows {
+		missing[i] = modulePrerequisite(row.RequiredModuleID, row.RequiredModuleSlug, row.RequiredModuleTitle)
+	}
+	respondLocked(w, "module is locked", missing)
+	return false
+}
+
+// requireLessonUnlocked is requireModuleUnlocked for a lesson: both the
+// lesson's module and the lesson itself must be unlocked.
+func requireLessonUnlocked(w http.ResponseWriter, r *http.Request, q *dbgen.Queries, editor bool, moduleID, lessonID uuid.UUID) bool {
+	if editor {
+		return true
+	}
+	if !requireModuleUnlocked(w, r, q, editor, moduleID) {
+		return false
+	}
+	userID, _ := middleware.GetUserID(r.Context())
+
+	rows, err := q.GetUnmetLessonPrerequisites(r.Context(), dbgen.GetUnmetLessonPrerequisitesParams{
+		LessonID: lessonID,
+		UserID:   userID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to check prerequisites")
+		return false
+	}
+	if len(rows) == 0 {
+		return true
+	}
+
+	missing := make([]prerequisite, len(rows))
+	for i, row := range rows {
+		missing[i] = prerequisite{
+			Type:       "lesson",
+			ID:         row.RequiredLessonID.String(),
+			ModuleSlug: row.ModuleSlug,
+			LessonSlug: row.RequiredLessonSlug,
+			Title:      row.RequiredLessonTitle,
+		}
+	}
+	respondLocked(w, "lesson is locked", missing)
+	return false
+}
+
+// ── Admin ────────────────────────────────────────────────────────────────────
+
+func (h *CurriculumHandler) GetModulePrerequisites(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	modules, err := h.queries.ListModulePrerequisites(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list prerequisites")
+		return
+	}
+
+	respondOK(w, map[string]any{"prerequisites": modules})
+}
+
+type modulePrerequisitesRequest struct {
+	ModuleIDs []string `json:"module_ids"`
+}
+
+// SetModulePrerequisites replaces the set of modules that must be completed
+// before this one unlocks.
+func (h *CurriculumHandler) SetModulePrerequisites(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	var req modulePrerequisitesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	required, ok := parseIDs(w, req.ModuleIDs, "module_ids")
+	if !ok {
+		return
+	}
+
+	if _, err := h.queries.GetModuleByID(r.Context(), id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "module not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to get module")
+		return
+	}
+
+	err = h.setPrerequisites(r.Context(), id, required,
+		func(q *dbgen.Queries) error { return q.DeleteModulePrerequisites(r.Context(), id) },
+		func(q *dbgen.Queries, requiredID uuid.UUID) error {
+			return q.AddModulePrerequisite(r.Context(), dbgen.AddModulePrerequisiteParams{
+				ModuleID:         id,
+				RequiredModuleID: requiredID,
+			})
+		},
+		func(q *dbgen.Queries) (map[uuid.UUID][]uuid.UUID, error) {
+			edges, err := q.ListModulePrerequisiteEdges(r.Context())
+			graph := make(map[uuid.UUID][]uuid.UUID)
+			for _, e := range edges {
+				graph[e.ModuleID] = append(graph[e.ModuleID], e.RequiredModuleID)
+			}
+			return graph, err
+		},
+	)
+	if !respondSetPrerequisites(w, err, "module") {
+		return
+	}
+
+	h.GetModulePrerequisites(w, r)
+}
+
+func (h *CurriculumHandler) GetLessonPrerequisites(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return
+	}
+
+	lessons, err := h.queries.ListLessonPrerequisites(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list prerequisites")
+		return
+	}
+
+	respondOK(w, map[string]any{"prerequisites": lessons})
+}
+
+type lessonPrerequisitesRequest struct {
+	LessonIDs []string `json:"lesson_ids"`
+}
+
+// SetLessonPrerequisites replaces the set of lessons, from any module, that
+// must be completed before this one unlocks.
+func (h *CurriculumHandler) SetLessonPrerequisites(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return
+	}
+
+	var req lessonPrerequisitesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	required, ok := parseIDs(w, req.LessonIDs, "lesson_ids")
+	if !ok {
+		return
+	}
+
+	if _, err := h.queries.GetLessonByID(r.Context(), id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "lesson not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to get lesson")
+		return
+	}
+
+	err = h.setPrerequisites(r.Context(), id, required,
+		func(q *dbgen.Queries) error { return q.DeleteLessonPrerequisites(r.Context(), id) },
+		func(q *dbgen.Queries, requiredID uuid.UUID) error {
+			return q.AddLessonPrerequisite(r.Context(), dbgen.AddLessonPrerequisiteParams{
+				LessonID:         id,
+				RequiredLessonID: requiredID,
+			})
+		},
+		func(q *dbgen.Queries) (map[uuid.UUID][]uuid.UUID, error) {
+			edges, err := q.ListLessonPrerequisiteEdges(r.Context())
+			graph := make(map[uuid.UUID][]uuid.UUID)
+			for _, e := range edges {
+				graph[e.LessonID] = append(graph[e.LessonID], e.RequiredLessonID)
+			}
+			return graph, err
+		},
+	)
+	if !respondSetPrerequisites(w, err, "lesson") {
+		return
+	}
+
+	h.GetLessonPrerequisites(w, r)
+}
+
+func parseIDs(w http.ResponseWriter, raw []string, field string) ([]uuid.UUID, bool) {
+	ids := make([]uuid.UUID, len(raw))
+	for i, s := range raw {
+		id, err := parseUUID(s)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid id in "+field)
+			return nil, false
+		}
+		ids[i] = id
+	}
+	return ids, true
+}
+
+var (
+	errSelfPrerequisite  = errors.New("an item cannot be its own prerequisite")
+	errPrerequisiteCycle = errors.New("prerequisites would form a cycle")
+)
+
+// setPrerequisites replaces id's prerequisites inside a transaction and rolls
+// back if the result would contain a cycle, which would lock content forever.
+func (h *CurriculumHandler) setPrerequisites(
+	ctx context.Context,
+	id uuid.UUID,
+	required []uuid.UUID,
+	remove func(q *dbgen.Queries) error,
+	add func(q *dbgen.Queries, requiredID uuid.UUID) error,
+	graph func(q *dbgen.Queries) (map[uuid.UUID][]uuid.UUID, error),
+) error {
+	for _, requiredID := range required {
+		if requiredID == id {
+			return errSelfPrerequisite
+		}
+	}
+
+	return appdb.WithTx(ctx, h.pool, func(tx pgx.Tx) error {
+		q := h.queries.WithTx(tx)
+
+		if err := remove(q); err != nil {
+			return err
+		}
+		for _, requiredID := range required {
+			if err := add(q, requiredID); err != nil {
+				return err
+			}
+		}
+
+		edges, err := graph(q)
+		if err != nil {
+			return err
+		}
+		if reachable(edges, id, id) {
+			return errPrerequisiteCycle
+		}
+		return nil
+	})
+}
+
+// reachable reports whether target can be reached from start by following
+// at least one edge.
+func reachable(edges map[uuid.UUID][]uuid.UUID, start, target uuid.UUID) bool {
+	seen := make(map[uuid.UUID]bool)
+	stack := append([]uuid.UUID(nil), edges[start]...)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n == target {
+			return true
+		}
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		stack = append(stack, edges[n]...)
+	}
+	return false
+}
+
+func respondSetPrerequisites(w http.ResponseWriter, err error, kind string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errSelfPrerequisite):
+		respondError(w, http.StatusBadRequest, errSelfPrerequisite.Error())
+	case errors.Is(err, errPrerequisiteCycle):
+		respondError(w, http.StatusConflict, errPrerequisiteCycle.Error())
+	case strings.Contains(err.Error(), "foreign key"):
+		respondError(w, http.StatusBadRequest, "unknown "+kind+" in prerequisites")
+	default:
+		respondError(w, http.StatusInternalServerError, "failed to set prerequisites")
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+// canReadPaidContent reports whether the caller may read lessons that aren't
+// previews: subscribers and content editors. Routes that free users can also
+// reach use it instead of the RequireActive middleware.
+func canReadPaidContent(r *http.Request, q *dbgen.Queries) (bool, error) {
+	if canEditContent(r, q) {
+		return true, nil
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		return false, nil
+	}
+	status, err := q.GetUserSubscriptionStatus(r.Context(), userID)
+	if err != nil {
+		return false, err
+	}
+	return status == dbgen.SubscriptionStatusActive, nil
+}
+
+// respondUpgradeRequired writes the 403 for paid content requested by a user
+// without an active subscription.
+func respondUpgradeRequired(w http.ResponseWriter) {
+	respond(w, http.StatusForbidden, map[string]any{
+		"error": "active subscription required",
+		"code":  "upgrade_required",
+	})
+}
+
+// lessonRef points at a lesson from a module listing.
+type lessonRef struct {
+	ID    string `json:"id"`
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/subscription"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/mailer"
+)
+
+// ExportMe returns everything we store about the user as a downloadable JSON
+// document (GDPR right of access). Secrets such as password and TOTP hashes
+// are left out.
+func (h *AuthHandler) ExportMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	lessons, err := h.queries.ExportUserLessonProgress(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export lesson progress")
+		return
+	}
+
+	skills, err := h.queries.ExportUserSkillProgress(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export skill progress")
+		return
+	}
+
+	archivedLessons, err := h.queries.ExportUserArchivedLessonProgress(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export lesson progress")
+		return
+	}
+
+	archivedSkills, err := h.queries.ExportUserArchivedSkillProgress(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export skill progress")
+		return
+	}
+
+	views, err := h.queries.ExportUserLessonViews(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export lesson views")
+		return
+	}
+
+	submissions, err := h.queries.ExportUserSubmissions(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export submissions")
+		return
+	}
+
+	xpEvents, err := h.queries.ExportUserXPEvents(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export xp")
+		return
+	}
+
+	badges, err := h.queries.ListUserBadges(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export badges")
+		return
+	}
+
+	certificates, err := h.queries.ListUserCertificates(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to export certificates")
+		return
+	}
+
+	profile := accountResponse(user)
+	profile["github_id"] = user.GithubID
+	profile["stripe_customer_id"] = user.StripeCustomerID
+	profile["stripe_subscription_id"] = user.StripeSubscriptionID
+	profile["updated_at"] = user.UpdatedAt
+
+	filename := fmt.Sprintf("levelup-export-%s.json", time.Now().UTC().Format("20060102"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	respondOK(w, map[string]any{
+		"exported_at":     time.Now().UTC(),
+		"profile":         profile,
+		"lesson_progress": lessons,
+		"skill_progress":  skills,
+		"lesson_views":    views,
+		"submissions":     submissions,
+
+		// Completions cleared by module resets
+		"archived_lesson_progress": archivedLessons,
+		"archived_skill_progress":  archivedSkills,
+
+		"xp_events":    xpEvents,
+		"badges":       badges,
+		"certificates": certificates,
+	})
+}
+
+type deleteMeRequest struct {
+	Password     string `json:"password"`
+	ConfirmEmail string `json:"confirm_email"`
+}
+
+// DeleteMe permanently deletes the account (GDPR right to erasure). Any Stripe
+// subscription is cancelled first so the user is never billed for a deleted
+// account; progress, submissions and sessions go with the user row.
+func (h *AuthHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	var req deleteMeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	// Accounts without a password (GitHub sign-in) confirm by typing their email.
+	if user.PasswordHash != "" {
+		if !auth.CheckPassword(req.Password, user.PasswordHash) {
+			respondError(w, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+	} else if req.ConfirmEmail != user.Email {
+		respondError(w, http.StatusBadRequest, "confirm_email must match your account email")
+		return
+	}
+
+	if err := cancelStripeSubscription(user); err != nil {
+		respondError(w, http.StatusBadGateway, "failed to cancel subscription")
+		return
+	}
+
+	if err := h.queries.DeleteUser(r.Context(), user.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to delete account")
+		return
+	}
+
+	h.mailer.Send(mailer.EmailJob{
+		To:       user.Email,
+		Subject:  "Your Level Up Backend account has been deleted",
+		Template: "account_deleted",
+		Data:     map[string]string{"name": user.Name},
+	})
+
+	respondOK(w, map[string]string{"status": "deleted"})
+}
+
+// cancelStripeSubscription cancels the user's subscription immediately. A
+// subscription Stripe no longer knows about counts as cancelled.
+func cancelStripeSubscription(user dbgen.User) error {
+	if user.StripeSubscriptionID == nil {
+		return nil
+	}
+	switch user.SubscriptionStatus {
+	case dbgen.SubscriptionStatusActive, dbgen.SubscriptionStatusPastDue:
+	default:
+		return nil
+	}
+
+	_, err := subscription.Cancel(*user.StripeSubscriptionID, nil)
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) && stripeErr.Code == stripe.ErrorCodeResourceMissing {
+		return nil
+	}
+	return err
+}
@@ -1,18 +1,26 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/gamification"
 	"github.com/anujgupta/level-up-backend/internal/middleware"
 )
 
 type ProgressHandler struct {
 	queries *dbgen.Queries
+	game    *gamification.Service
 }
 
-func NewProgressHandler(q *dbgen.Queries) *ProgressHandler {
-	return &ProgressHandler{queries: q}
+func NewProgressHandler(q *dbgen.Queries, game *gamification.Service) *ProgressHandler {
+	return &ProgressHandler{queries: q, game: game}
 }
 
 func (h *ProgressHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
@@ -44,8 +52,68 @@ func (h *ProgressHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
 		skillIDs[i] = id.String()
 	}
 
+	summary, err := h.game.Summary(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to get xp")
+		return
+	}
+
 	respondOK(w, map[string]any{
 		"completed_lesson_ids": lessonIDs,
 		"completed_skill_ids":  skillIDs,
+		"level":                summary.Level,
+		"badges":               summary.Badges,
+	})
+}
+
+// canRecordProgress reports whether reading activity on this request should be
+// recorded for the user: not while an admin impersonates them, and not for
+// personal access tokens without the progress scope.
+func canRecordProgress(r *http.Request) bool {
+	if _, impersonating := middleware.GetImpersonatorID(r.Context()); impersonating {
+		return false
+	}
+	scopes, isToken := middleware.GetScopes(r.Context())
+	return !isToken || slices.Contains(scopes, auth.ScopeProgress)
+}
+
+// GetResume returns the unfinished lesson the user viewed most recently, with
+// their reading position, and the next lesson to read after it. Either is null
+// when there is nothing to suggest.
+func (h *ProgressHandler) GetResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	var resume *dbgen.GetResumeLessonRow
+	var exclude pgtype.UUID
+	row, err := h.queries.GetResumeLesson(r.Context(), userID)
+	switch {
+	case err == nil:
+		resume = &row
+		exclude = pgtype.UUID{Bytes: row.LessonID, Valid: true}
+	case !errors.Is(err, pgx.ErrNoRows):
+		respondError(w, http.StatusInternalServerError, "failed to get last viewed lesson")
+		return
+	}
+
+	var next *dbgen.GetNextRecommendedLessonRow
+	nextRow, err := h.queries.GetNextRecommendedLesson(r.Context(), dbgen.GetNextRecommendedLessonParams{
+		ExcludeLessonID: exclude,
+		UserID:          userID,
+	})
+	switch {
+	case err == nil:
+		next = &nextRow
+	case !errors.Is(err, pgx.ErrNoRows):
+		respondError(w, http.StatusInternalServerError, "failed to get next lesson")
+		return
+	}
+
+	respondOK(w, map[string]any{
+		"resume": resume,
+		"next":   next,
 	})
 }
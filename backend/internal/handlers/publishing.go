@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	appdb "github.com/anujgupta/level-up-backend/internal/db"
+	"github.com/anujgupta/level-up-backend/internal/diff"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+// canEditContent reports whether the caller may see draft and unscheduled
+// curriculum. Permission lookup failures are treated as "no".
+func canEditContent(r *http.Request, q *dbgen.Queries) bool {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		return false
+	}
+	allowed, err := q.UserHasPermission(r.Context(), dbgen.UserHasPermissionParams{
+		UserID:     userID,
+		Permission: auth.PermContentEdit,
+	})
+	return err == nil && allowed
+}
+
+// moduleBySlug looks a module up by slug. Learners only find modules that are
+// published (or whose scheduled time has passed); editors find any module.
+func moduleBySlug(r *http.Request, q *dbgen.Queries, slug string) (dbgen.Module, error) {
+	if canEditContent(r, q) {
+		return q.GetModuleBySlug(r.Context(), slug)
+	}
+	return q.GetPublishedModuleBySlug(r.Context(), slug)
+}
+
+// saveRevision records the lesson's current title and content as its next
+// revision, authored by the caller.
+func (h *CurriculumHandler) saveRevision(r *http.Request, q *dbgen.Queries, lesson dbgen.Lesson) error {
+	var author pgtype.UUID
+	if userID, ok := middleware.GetUserID(r.Context()); ok {
+		author = pgtype.UUID{Bytes: userID, Valid: true}
+	}
+
+	_, err := q.CreateLessonRevision(r.Context(), dbgen.CreateLessonRevisionParams{
+		LessonID: lesson.ID,
+		Title:    lesson.Title,
+		Content:  lesson.Content,
+		AuthorID: author,
+	})
+	return err
+}
+
+type statusRequest struct {
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// parse validates the requested status and works out publish_at: scheduled
+// content needs a time, published content defaults to now, and drafts and
+// archived content have none.
+func (req statusRequest) parse() (dbgen.ContentStatus, pgtype.Timestamptz, string) {
+	status := dbgen.ContentStatus(req.Status)
+	if !status.Valid() {
+		return "", pgtype.Timestamptz{}, "invalid status: must be draft, scheduled, published, or archived"
+	}
+
+	switch status {
+	case dbgen.ContentStatusScheduled:
+		if req.PublishAt == nil {
+			return "", pgtype.Timestamptz{}, "publish_at is required when scheduling"
+		}
+		return status, pgtype.Timestamptz{Time: *req.PublishAt, Valid: true}, ""
+	case dbgen.ContentStatusPublished:
+		return status, pgtype.Timestamptz{Time: time.Now(), Valid: true}, ""
+	default:
+		return status, pgtype.Timestamptz{}, ""
+	}
+}
+
+func (h *CurriculumHandler) SetModuleStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid module id")
+		return
+	}
+
+	var req statusRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	status, publishAt, msg := req.parse()
+	if msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	module, err := h.queries.SetModuleStatus(r.Context(), dbgen.SetModuleStatusParams{
+		ID:        id,
+		Status:    status,
+		PublishAt: publishAt,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "module not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to update module status")
+		return
+	}
+
+	respondOK(w, module)
+}
+
+// SetLessonStatus changes a lesson's status. Publishing or scheduling pins the
+// latest revision as the one learners see; later edits stay unpublished until
+// the lesson is published again.
+func (h *CurriculumHandler) SetLessonStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return
+	}
+
+	var req statusRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	status, publishAt, msg := req.parse()
+	if msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	params := dbgen.SetLessonStatusParams{
+		ID:        id,
+		Status:    status,
+		PublishAt: publishAt,
+	}
+	if status == dbgen.ContentStatusPublished || status == dbgen.ContentStatusScheduled {
+		latest, err := h.queries.GetLatestLessonRevision(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				respondError(w, http.StatusNotFound, "lesson not found")
+				return
+			}
+			respondError(w, http.StatusInternalServerError, "failed to fetch latest revision")
+			return
+		}
+		params.PublishedRevisionID = pgtype.UUID{Bytes: latest.ID, Valid: true}
+	}
+
+	lesson, err := h.queries.SetLessonStatus(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "lesson not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to update lesson status")
+		return
+	}
+
+	respondOK(w, lesson)
+}
+
+func (h *CurriculumHandler) ListLessonRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return
+	}
+
+	revisions, err := h.queries.ListLessonRevisions(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list revisions")
+		return
+	}
+
+	respondOK(w, map[string]any{"revisions": revisions})
+}
+
+func (h *CurriculumHandler) GetLessonRevision(w http.ResponseWriter, r *http.Request) {
+	revision, ok := h.revisionFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	respondOK(w, revision)
+}
+
+// DiffLessonRevision compares a revision with an earlier one: ?against=N, or
+// the revision right before it by default.
+func (h *CurriculumHandler) DiffLessonRevision(w http.ResponseWriter, r *http.Request) {
+	revision, ok := h.revisionFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	againstNumber := revision.RevisionNumber - 1
+	if s := r.URL.Query().Get("against"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || n < 0 {
+			respondError(w, http.StatusBadRequest, "invalid against revision")
+			return
+		}
+		againstNumber = int32(n)
+	}
+
+	// Revision 0 is the empty lesson, so the first revision diffs as all new.
+	var against dbgen.LessonRevision
+	if againstNumber > 0 {
+		var err error
+		against, err = h.queries.GetLessonRevision(r.Context(), dbgen.GetLessonRevisionParams{
+			LessonID:       revision.LessonID,
+			RevisionNumber: againstNumber,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				respondError(w, http.StatusNotFound, "revision not found")
+				return
+			}
+			respondError(w, http.StatusInternalServerError, "failed to fetch revision")
+			return
+		}
+	}
+
+	lines, err := diff.Lines(against.Content, revision.Content)
+	if err != nil {
+		if errors.Is(err, diff.ErrTooLarge) {
+			respondError(w, http.StatusUnprocessableEntity, "revisions differ too much to diff")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "failed to diff revisions")
+		return
+	}
+	respondOK(w, map[string]any{
+		"from":      againstNumber,
+		"to":        revision.RevisionNumber,
+		"old_title": against.Title,
+		"new_title": revision.Title,
+		"lines":     lines,
+		"unified":   diff.Unified(lines),
+	})
+}
+
+// RestoreLessonRevision copies an old revision back into the working copy.
+// The restore is itself saved as a new revision, so history is never lost.
+func (h *CurriculumHandler) RestoreLessonRevision(w http.ResponseWriter, r *http.Request) {
+	revision, ok := h.revisionFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	var lesson dbgen.Lesson
+	err := appdb.WithTx(r.Context(), h.pool, func(tx pgx.Tx) error {
+		q := h.queries.WithTx(tx)
+
+		var err error
+		lesson, err = q.UpdateLesson(r.Context(), dbgen.UpdateLessonParams{
+			ID:      revision.LessonID,
+			Title:   &revision.Title,
+			Content: &revision.Content,
+		})
+		if err != nil {
+			return err
+		}
+		return h.saveRevision(r, q, lesson)
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to restore revision")
+		return
+	}
+
+	respondOK(w, lesson)
+}
+
+// revisionFromURL loads the revision identified by the {id} and {revision}
+// URL params, writing an error response on failure.
+func (h *CurriculumHandler) revisionFromURL(w http.ResponseWriter, r *http.Request) (dbgen.LessonRevision, bool) {
+	lessonID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid lesson id")
+		return dbgen.LessonRevision{}, false
+	}
+
+	number, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil || number < 1 {
+		respondError(w, http.StatusBadRequest, "invalid revision number")
+		return dbgen.LessonRevision{}, false
+	}
+
+	revision, err := h.queries.GetLessonRevision(r.Context(), dbgen.GetLessonRevisionParams{
+		LessonID:       lessonID,
+		RevisionNumber: int32(number),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "revision not found")
+			return dbgen.LessonRevision{}, false
+		}
+		respondError(w, http.StatusInternalServerError, "failed to fetch revision")
+		return dbgen.LessonRevision{}, false
+	}
+	return revision, true
+}
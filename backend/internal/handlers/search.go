@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+)
+
+const (
+	searchPageSize     = 20
+	searchMaxQueryLen  = 200
+	searchMatchStart   = "\x02"
+	searchMatchStop    = "\x03"
+	searchSelectors    = "StartSel=" + searchMatchStart + ", StopSel=" + searchMatchStop
+	searchTitleOptions = "HighlightAll=true, " + searchSelectors
+	// Snippets are up to two fragments of about 30 words around the matches.
+	searchSnippetOptions = "MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \", " + searchSelectors
+)
+
+// ts_headline marks matches with control characters rather than HTML, so the
+// matched text itself can be escaped before <mark> tags are added.
+var searchMarkReplacer = strings.NewReplacer(searchMatchStart, "<mark>", searchMatchStop, "</mark>")
+
+type SearchHandler struct {
+	queries *dbgen.Queries
+}
+
+func NewSearchHandler(q *dbgen.Queries) *SearchHandler {
+	return &SearchHandler{queries: q}
+}
+
+type searchResult struct {
+	Kind       string  `json:"kind"`
+	ID         string  `json:"id"`
+	ModuleSlug string  `json:"module_slug"`
+	LessonSlug string  `json:"lesson_slug,omitempty"`
+	Title      string  `json:"title"`
+	Snippet    string  `json:"snippet,omitempty"`
+	Rank       float32 `json:"rank"`
+}
+
+// Search runs a full-text search over published modules, lessons and skills.
+// Titles and snippets are HTML with matches wrapped in <mark>. Lesson body
+// excerpts are part of the paid content, so only subscribers get them, except
+// for preview lessons.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		respondError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	if len(query) > searchMaxQueryLen {
+		respondError(w, http.StatusBadRequest, "q is too long")
+		return
+	}
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	subscribed, err := canReadPaidContent(r, h.queries)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to verify subscription")
+		return
+	}
+
+	rows, err := h.queries.SearchContent(r.Context(), dbgen.SearchContentParams{
+		Query:           query,
+		TitleOptions:    searchTitleOptions,
+		HeadlineOptions: searchSnippetOptions,
+		IncludeExcerpts: subscribed,
+		PageLimit:       searchPageSize,
+		PageOffset:      int32(offset),
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "search failed")
+		return
+	}
+
+	results := make([]searchResult, len(rows))
+	for i, row := range rows {
+		results[i] = searchResult{
+			Kind:       row.Kind,
+			ID:         row.ID.String(),
+			ModuleSlug: row.ModuleSlug,
+			LessonSlug: row.LessonSlug,
+			Title:      highlight(row.Title),
+			Snippet:    highlight(row.Snippet),
+			Rank:       row.Rank,
+		}
+	}
+
+	respondOK(w, map[string]any{
+		"query":           query,
+		"results":         results,
+		"excerpts_locked": !subscribed,
+	})
+}
+
+func highlight(s string) string {
+	return searchMarkReplacer.Replace(html.EscapeString(s))
+}
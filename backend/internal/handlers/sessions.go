@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+)
+
+// issueTokens mints a token pair for the user and records the refresh token in
+// the refresh token store. Pass uuid.New() as sessionID to start a new session,
+// or an existing family ID when rotating. mfa marks sessions that passed 2FA.
+func issueTokens(r *http.Request, q *dbgen.Queries, a *auth.Service, user dbgen.User, sessionID uuid.UUID, mfa bool) (*auth.TokenPair, error) {
+	tokens, err := a.GenerateTokenPair(user.ID, user.Role, sessionID, mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := q.CreateRefreshToken(r.Context(), dbgen.CreateRefreshTokenParams{
+		ID:        tokens.RefreshTokenID,
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: auth.HashToken(tokens.RefreshToken),
+		UserAgent: r.UserAgent(),
+		IpAddress: r.RemoteAddr,
+		ExpiresAt: pgtype.Timestamptz{Time: tokens.RefreshExpiresAt, Valid: true},
+	}); err != nil {
+		return nil, fmt.Errorf("store refresh token: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// userResponse is the public view of a user returned alongside a token pair.
+func userResponse(user dbgen.User) map[string]any {
+	return map[string]any{
+		"id":                  user.ID,
+		"email":               user.Email,
+		"name":                user.Name,
+		"subscription_status": user.SubscriptionStatus,
+		"email_verified":      user.EmailVerifiedAt.Valid,
+		"github_login":        user.GithubLogin,
+	}
+}
+
+// respondLogin finishes a primary sign-in (password or OAuth). Users with
+// two-factor authentication get a short-lived MFA challenge instead of a
+// session; they exchange it for tokens via CompleteMFALogin.
+func respondLogin(w http.ResponseWriter, r *http.Request, q *dbgen.Queries, a *auth.Service, user dbgen.User, status int) {
+	if user.TotpEnabledAt.Valid {
+		challenge, err := a.GenerateMFAChallengeToken(user.ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to generate mfa challenge")
+			return
+		}
+		respondOK(w, map[string]any{
+			"mfa_required": true,
+			"mfa_token":    challenge,
+		})
+		return
+	}
+
+	tokens, err := issueTokens(r, q, a, user, uuid.New(), false)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate tokens")
+		return
+	}
+
+	respond(w, status, map[string]any{
+		"access_token":  tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"user":          userResponse(user),
+	})
+}
@@ -8,21 +8,23 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/gamification"
 	"github.com/anujgupta/level-up-backend/internal/middleware"
 )
 
 type SkillsHandler struct {
 	queries *dbgen.Queries
+	game    *gamification.Service
 }
 
-func NewSkillsHandler(q *dbgen.Queries) *SkillsHandler {
-	return &SkillsHandler{queries: q}
+func NewSkillsHandler(q *dbgen.Queries, game *gamification.Service) *SkillsHandler {
+	return &SkillsHandler{queries: q, game: game}
 }
 
 func (h *SkillsHandler) GetModuleSkills(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 
-	module, err := h.queries.GetModuleBySlug(r.Context(), slug)
+	module, err := moduleBySlug(r, h.queries, slug)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			respondError(w, http.StatusNotFound, "module not found")
@@ -32,6 +34,10 @@ func (h *SkillsHandler) GetModuleSkills(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if !requireModuleUnlocked(w, r, h.queries, canEditContent(r, h.queries), module.ID) {
+		return
+	}
+
 	skills, err := h.queries.GetSkillsByModule(r.Context(), module.ID)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "failed to get skills")
@@ -70,7 +76,8 @@ func (h *SkillsHandler) CompleteSkill(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := h.queries.GetSkillByID(r.Context(), skillID); err != nil {
+	skill, err := h.queries.GetSkillByID(r.Context(), skillID)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			respondError(w, http.StatusNotFound, "skill not found")
 			return
@@ -79,6 +86,10 @@ func (h *SkillsHandler) CompleteSkill(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !requireModuleUnlocked(w, r, h.queries, canEditContent(r, h.queries), skill.ModuleID) {
+		return
+	}
+
 	if err := h.queries.MarkSkillComplete(r.Context(), dbgen.MarkSkillCompleteParams{
 		UserID:  userID,
 		SkillID: skillID,
@@ -87,5 +98,42 @@ func (h *SkillsHandler) CompleteSkill(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondOK(w, map[string]string{"status": "completed"})
+	// Awards are idempotent, so if this fails a retry picks it up.
+	reward, err := h.game.Award(r.Context(), userID, dbgen.XpSourceSkill, skillID, gamification.SkillXP)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to award xp")
+		return
+	}
+
+	respondOK(w, map[string]any{"status": "completed", "reward": reward})
+}
+
+// UncompleteSkill undoes CompleteSkill. It succeeds whether or not the skill
+// was completed, so retries are harmless.
+func (h *SkillsHandler) UncompleteSkill(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	skillID, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid skill id")
+		return
+	}
+
+	if _, err := h.queries.UnmarkSkillComplete(r.Context(), dbgen.UnmarkSkillCompleteParams{
+		UserID:  userID,
+		SkillID: skillID,
+	}); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to mark skill incomplete")
+		return
+	}
+	if err := h.game.Revoke(r.Context(), userID, dbgen.XpSourceSkill, skillID); err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke xp")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "not_completed"})
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
 	"github.com/anujgupta/level-up-backend/internal/middleware"
 )
 
@@ -23,7 +24,7 @@ func NewSubmissionsHandler(q *dbgen.Queries) *SubmissionsHandler {
 func (h *SubmissionsHandler) GetAssignment(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 
-	module, err := h.queries.GetModuleBySlug(r.Context(), slug)
+	module, err := moduleBySlug(r, h.queries, slug)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			respondError(w, http.StatusNotFound, "module not found")
@@ -84,7 +85,8 @@ func (h *SubmissionsHandler) CreateSubmission(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if _, err := h.queries.GetAssignmentByID(r.Context(), assignmentID); err != nil {
+	assignment, err := h.queries.GetAssignmentByID(r.Context(), assignmentID)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			respondError(w, http.StatusNotFound, "assignment not found")
 			return
@@ -93,6 +95,10 @@ func (h *SubmissionsHandler) CreateSubmission(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if !requireModuleUnlocked(w, r, h.queries, canEditContent(r, h.queries), assignment.ModuleID) {
+		return
+	}
+
 	submission, err := h.queries.CreateSubmission(r.Context(), dbgen.CreateSubmissionParams{
 		AssignmentID:   assignmentID,
 		UserID:         userID,
@@ -151,10 +157,19 @@ func (h *SubmissionsHandler) GetSubmission(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	role, _ := middleware.GetRole(r.Context())
-	if submission.UserID != userID && role != "admin" {
-		respondError(w, http.StatusForbidden, "access denied")
-		return
+	if submission.UserID != userID {
+		allowed, err := h.queries.UserHasPermission(r.Context(), dbgen.UserHasPermissionParams{
+			UserID:     userID,
+			Permission: auth.PermSubmissionsRead,
+		})
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to check permissions")
+			return
+		}
+		if !allowed {
+			respondError(w, http.StatusForbidden, "access denied")
+			return
+		}
 	}
 
 	respondOK(w, submission)
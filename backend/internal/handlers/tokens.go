@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+	"github.com/anujgupta/level-up-backend/internal/auth"
+	"github.com/anujgupta/level-up-backend/internal/middleware"
+)
+
+const maxTokenExpiryDays = 365
+
+type TokensHandler struct {
+	queries *dbgen.Queries
+}
+
+func NewTokensHandler(q *dbgen.Queries) *TokensHandler {
+	return &TokensHandler{queries: q}
+}
+
+type tokenItem struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+func toTokenItem(t dbgen.PersonalAccessToken) tokenItem {
+	return tokenItem{
+		ID:         t.ID.String(),
+		Name:       t.Name,
+		Prefix:     t.TokenPrefix,
+		Scopes:     t.Scopes,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+func (h *TokensHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	tokens, err := h.queries.ListPersonalAccessTokens(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to list tokens")
+		return
+	}
+
+	result := make([]tokenItem, len(tokens))
+	for i, t := range tokens {
+		result[i] = toTokenItem(t)
+	}
+
+	respondOK(w, map[string]any{"tokens": result})
+}
+
+type createTokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
+
+// CreateToken issues a personal access token. The plaintext token is only
+// returned here; afterwards only its prefix is shown.
+func (h *TokensHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	var req createTokenRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one scope is required")
+		return
+	}
+	for _, s := range req.Scopes {
+		if !auth.ValidScope(s) {
+			respondError(w, http.StatusBadRequest, "invalid scope: must be read, progress, or submissions")
+			return
+		}
+	}
+	slices.Sort(req.Scopes)
+	req.Scopes = slices.Compact(req.Scopes)
+
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxTokenExpiryDays {
+		respondError(w, http.StatusBadRequest, "expires_in_days must be between 0 and 365")
+		return
+	}
+	var expiresAt pgtype.Timestamptz
+	if req.ExpiresInDays > 0 {
+		expiresAt = pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, req.ExpiresInDays), Valid: true}
+	}
+
+	plaintext, err := auth.GeneratePersonalAccessToken()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to generate token")
+		return
+	}
+
+	token, err := h.queries.CreatePersonalAccessToken(r.Context(), dbgen.CreatePersonalAccessTokenParams{
+		UserID:      userID,
+		Name:        req.Name,
+		TokenHash:   auth.HashToken(plaintext),
+		TokenPrefix: plaintext[:len(auth.PersonalAccessTokenPrefix)+6],
+		Scopes:      req.Scopes,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to create token")
+		return
+	}
+
+	respondCreated(w, map[string]any{
+		"token":   plaintext,
+		"details": toTokenItem(token),
+	})
+}
+
+func (h *TokensHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "not authenticated")
+		return
+	}
+
+	id, err := parseUUID(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	revoked, err := h.queries.RevokePersonalAccessToken(r.Context(), dbgen.RevokePersonalAccessTokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to revoke token")
+		return
+	}
+	if revoked == 0 {
+		respondError(w, http.StatusNotFound, "token not found")
+		return
+	}
+
+	respondOK(w, map[string]string{"status": "revoked"})
+}
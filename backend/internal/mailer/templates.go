@@ -20,6 +20,66 @@ func renderTemplate(name string, data any) string {
 		return fmt.Sprintf(`<html><body>
 <h2>Hey %s — your payment failed</h2>
 <p>Please update your billing info to keep access to Level Up Backend.</p>
+</body></html>`, name_)
+
+	case "verify_email":
+		return fmt.Sprintf(`<html><body>
+<h2>Hey %s — confirm your email</h2>
+<p>Please confirm this is your email address to finish setting up your account.</p>
+<p><a href="%s">Verify my email</a></p>
+<p>This link expires in %s.</p>
+</body></html>`, name_, d["verify_url"], d["expires_in"])
+
+	case "password_reset":
+		return fmt.Sprintf(`<html><body>
+<h2>Hey %s — reset your password</h2>
+<p>We received a request to reset your Level Up Backend password.</p>
+<p><a href="%s">Choose a new password</a></p>
+<p>This link expires in %s. If you didn't ask for a reset, you can ignore this email.</p>
+</body></html>`, name_, d["reset_url"], d["expires_in"])
+
+	case "account_locked":
+		return fmt.Sprintf(`<html><body>
+<h2>Hey %s — your account has been locked</h2>
+<p>We saw too many failed sign-in attempts, so we've locked your account until %s.</p>
+<p>If this wasn't you, <a href="%s">reset your password</a> once the lock expires.</p>
+</body></html>`, name_, d["locked_until"], d["reset_url"])
+
+	case "confirm_email_change":
+		return fmt.Sprintf(`<html><body>
+<h2>Hey %s — confirm your new email</h2>
+<p>You asked to change the email on your Level Up Backend account to this address.</p>
+<p><a href="%s">Confirm my new email</a></p>
+<p>This link expires in %s. If you didn't ask for this, you can ignore this email.</p>
+</body></html>`, name_, d["confirm_url"], d["expires_in"])
+
+	case "approve_email_change":
+		return fmt.Sprintf(`<html><body>
+<h2>Hey %s — approve your email change</h2>
+<p>You asked to change the email on your Level Up Backend account to %s.</p>
+<p><a href="%s">Approve the change</a></p>
+<p>This link expires in %s. If you didn't ask for this, ignore this email and sign out your other sessions.</p>
+</body></html>`, name_, d["new_email"], d["approve_url"], d["expires_in"])
+
+	case "email_changed":
+		return fmt.Sprintf(`<html><body>
+<h2>Hey %s — your email was changed</h2>
+<p>The email on your Level Up Backend account was changed to %s.</p>
+<p>If this wasn't you, contact support right away.</p>
+</body></html>`, name_, d["new_email"])
+
+	case "password_changed":
+		return fmt.Sprintf(`<html><body>
+<h2>Hey %s — your password was changed</h2>
+<p>The password on your Level Up Backend account was just changed and your other sessions were signed out.</p>
+<p>If this wasn't you, <a href="%s">reset your password</a> right away.</p>
+</body></html>`, name_, d["reset_url"])
+
+	case "account_deleted":
+		return fmt.Sprintf(`<html><body>
+<h2>Goodbye %s</h2>
+<p>Your Level Up Backend account and all of its data have been permanently deleted.</p>
+<p>Any active subscription has been cancelled and you won't be charged again.</p>
 </body></html>`, name_)
 
 	default:
@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Cache holds rendered documents keyed by lesson revision. Revisions never
+// change once written, so entries never go stale; the least recently used
+// entry is evicted once the cache is full.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	entries  map[uuid.UUID]*list.Element
+}
+
+type cacheEntry struct {
+	revisionID uuid.UUID
+	doc        Document
+}
+
+func NewCache(capacity int) *Cache {
+	return &Cache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[uuid.UUID]*list.Element, capacity),
+	}
+}
+
+// Render returns the rendered revision, rendering source on a cache miss.
+func (c *Cache) Render(revisionID uuid.UUID, source string) (Document, error) {
+	c.mu.Lock()
+	if el, ok := c.entries[revisionID]; ok {
+		c.order.MoveToFront(el)
+		doc := el.Value.(cacheEntry).doc
+		c.mu.Unlock()
+		return doc, nil
+	}
+	c.mu.Unlock()
+
+	// Render outside the lock; two concurrent misses for one revision just
+	// render it twice.
+	doc, err := Render(source)
+	if err != nil {
+		return Document{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[revisionID]; !ok {
+		c.entries[revisionID] = c.order.PushFront(cacheEntry{revisionID: revisionID, doc: doc})
+		if c.order.Len() > c.capacity {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(cacheEntry).revisionID)
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,151 @@
+// Package markdown renders lesson Markdown to sanitized HTML, with a table of
+// contents and a reading-time estimate.
+package markdown
+
+import (
+	"bytes"
+	"math"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Reading speeds used for the estimate. Code is read far more slowly than
+// prose, and the concurrency lessons are mostly code.
+const (
+	proseWordsPerMinute = 200
+	codeWordsPerMinute  = 100
+)
+
+// Headings deeper than this are left out of the table of contents.
+const maxTOCLevel = 3
+
+// Heading is one table of contents entry. ID is the anchor of the heading in
+// the rendered HTML.
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+type Document struct {
+	HTML           string    `json:"html"`
+	TOC            []Heading `json:"toc"`
+	ReadingMinutes int       `json:"reading_minutes"`
+}
+
+var md = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		highlighting.NewHighlighting(
+			highlighting.WithStyle("github"),
+			highlighting.WithFormatOptions(chromahtml.TabWidth(4)),
+		),
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+var policy = newPolicy()
+
+// newPolicy starts from bluemonday's user-generated-content policy and allows
+// the inline styles chroma emits for highlighted code.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowStyles("color", "background-color", "font-weight", "font-style", "text-decoration").
+		OnElements("pre", "span")
+	return p
+}
+
+// Render converts Markdown source to sanitized HTML. Lesson content is
+// authored by staff, but it's sanitized anyway since it is served to every
+// learner.
+func Render(source string) (Document, error) {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src))
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, src, doc); err != nil {
+		return Document{}, err
+	}
+
+	return Document{
+		HTML:           policy.Sanitize(buf.String()),
+		TOC:            tableOfContents(doc, src),
+		ReadingMinutes: readingMinutes(doc, src),
+	}, nil
+}
+
+func tableOfContents(doc ast.Node, src []byte) []Heading {
+	toc := []Heading{}
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		// The lesson title is the only level-1 heading; it isn't a section.
+		if heading.Level < 2 || heading.Level > maxTOCLevel {
+			return ast.WalkSkipChildren, nil
+		}
+
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		toc = append(toc, Heading{
+			Level: heading.Level,
+			ID:    string(idBytes),
+			Text:  inlineText(heading, src),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+// inlineText concatenates the text of n's inline descendants.
+func inlineText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.Text:
+			sb.Write(t.Value(src))
+		case *ast.String:
+			sb.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+// readingMinutes estimates reading time from the word counts of prose and
+// code, rounded up to a whole minute.
+func readingMinutes(doc ast.Node, src []byte) int {
+	var proseWords, codeWords int
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := t.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				seg := lines.At(i)
+				codeWords += len(strings.Fields(string(seg.Value(src))))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			proseWords += len(strings.Fields(string(t.Value(src))))
+		}
+		return ast.WalkContinue, nil
+	})
+
+	minutes := float64(proseWords)/proseWordsPerMinute + float64(codeWords)/codeWordsPerMinute
+	return max(1, int(math.Ceil(minutes)))
+}
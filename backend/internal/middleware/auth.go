@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
 	"github.com/anujgupta/level-up-backend/internal/auth"
 	"github.com/google/uuid"
 )
@@ -12,8 +13,12 @@ import (
 type contextKey string
 
 const (
-	ContextKeyUserID contextKey = "userID"
-	ContextKeyRole   contextKey = "role"
+	ContextKeyUserID       contextKey = "userID"
+	ContextKeyRole         contextKey = "role"
+	ContextKeySessionID    contextKey = "sessionID"
+	ContextKeyMFA          contextKey = "mfa"
+	ContextKeyScopes       contextKey = "scopes"
+	ContextKeyImpersonator contextKey = "impersonator"
 )
 
 func GetUserID(ctx context.Context) (uuid.UUID, bool) {
@@ -26,8 +31,35 @@ func GetRole(ctx context.Context) (string, bool) {
 	return role, ok
 }
 
-// Authenticate validates the Bearer JWT and injects userID + role into context.
-func Authenticate(authSvc *auth.Service) func(http.Handler) http.Handler {
+// GetSessionID returns the refresh token family the access token was issued for.
+func GetSessionID(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(ContextKeySessionID).(uuid.UUID)
+	return id, ok
+}
+
+// GetMFA reports whether the session passed two-factor authentication.
+func GetMFA(ctx context.Context) bool {
+	mfa, _ := ctx.Value(ContextKeyMFA).(bool)
+	return mfa
+}
+
+// GetScopes returns the scopes of the personal access token used for the
+// request. ok is false for JWT sessions, which are not scoped.
+func GetScopes(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(ContextKeyScopes).([]string)
+	return scopes, ok
+}
+
+// GetImpersonatorID returns the admin acting as the user, if the request is
+// made with an impersonation token.
+func GetImpersonatorID(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(ContextKeyImpersonator).(uuid.UUID)
+	return id, ok
+}
+
+// Authenticate validates the Bearer JWT or personal access token and injects
+// userID + role into context.
+func Authenticate(authSvc *auth.Service, queries *dbgen.Queries) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -37,6 +69,11 @@ func Authenticate(authSvc *auth.Service) func(http.Handler) http.Handler {
 			}
 
 			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+			if strings.HasPrefix(tokenStr, auth.PersonalAccessTokenPrefix) {
+				authenticateToken(queries, tokenStr, next, w, r)
+				return
+			}
+
 			claims, err := authSvc.ParseToken(tokenStr)
 			if err != nil {
 				respondUnauthorized(w, "invalid or expired token")
@@ -50,7 +87,36 @@ func Authenticate(authSvc *auth.Service) func(http.Handler) http.Handler {
 
 			ctx := context.WithValue(r.Context(), ContextKeyUserID, claims.UserID)
 			ctx = context.WithValue(ctx, ContextKeyRole, claims.Role)
+			ctx = context.WithValue(ctx, ContextKeySessionID, claims.SessionID)
+			ctx = context.WithValue(ctx, ContextKeyMFA, claims.MFA)
+
+			// Impersonation sessions can be ended early by the admin, so
+			// they are checked against the audit table on every request.
+			if claims.ImpersonatorID != uuid.Nil {
+				active, err := queries.IsImpersonationSessionActive(r.Context(), claims.SessionID)
+				if err != nil || !active {
+					respondUnauthorized(w, "impersonation session has ended")
+					return
+				}
+				ctx = context.WithValue(ctx, ContextKeyImpersonator, claims.ImpersonatorID)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+func authenticateToken(queries *dbgen.Queries, tokenStr string, next http.Handler, w http.ResponseWriter, r *http.Request) {
+	token, err := queries.GetActivePersonalAccessToken(r.Context(), auth.HashToken(tokenStr))
+	if err != nil {
+		respondUnauthorized(w, "invalid or expired token")
+		return
+	}
+
+	// Best effort: a failed timestamp update should not fail the request.
+	_ = queries.TouchPersonalAccessToken(r.Context(), token.ID)
+
+	ctx := context.WithValue(r.Context(), ContextKeyUserID, token.UserID)
+	ctx = context.WithValue(ctx, ContextKeyRole, token.Role)
+	ctx = context.WithValue(ctx, ContextKeyScopes, token.Scopes)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import "net/http"
+
+// BlockImpersonation rejects requests made with an impersonation token. Use it
+// for routes that change the account or its billing.
+// Must be used after Authenticate middleware.
+func BlockImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, impersonating := GetImpersonatorID(r.Context()); impersonating {
+			respondForbidden(w, "not allowed while impersonating a user")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import "net/http"
+
+// RequireMFA rejects sessions that did not pass two-factor authentication.
+// Must be used after Authenticate middleware.
+func RequireMFA(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !GetMFA(r.Context()) {
+			respondForbidden(w, "two-factor authentication required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
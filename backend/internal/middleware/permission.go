@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+
+	dbgen "github.com/anujgupta/level-up-backend/generated/db"
+)
+
+// RequirePermission rejects requests whose user's role has not been granted
+// permission in the role_permissions table. The role is read from the users
+// table, not the access token, so a demotion applies before the token expires.
+// Must be used after Authenticate middleware.
+func RequirePermission(queries *dbgen.Queries, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserID(r.Context())
+			if !ok {
+				respondForbidden(w, "permission "+permission+" required")
+				return
+			}
+
+			allowed, err := queries.UserHasPermission(r.Context(), dbgen.UserHasPermissionParams{
+				UserID:     userID,
+				Permission: permission,
+			})
+			if err != nil {
+				respondInternalError(w, "could not verify permissions")
+				return
+			}
+			if !allowed {
+				respondForbidden(w, "permission "+permission+" required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+)
+
+// RequireScope lets personal access tokens through only if they carry scope.
+// JWT sessions are not scoped and always pass.
+// Must be used after Authenticate middleware.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, isToken := GetScopes(r.Context())
+			if isToken && !slices.Contains(scopes, scope) {
+				respondForbidden(w, "token is missing the "+scope+" scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects personal access tokens. Use it for routes that manage
+// the account, billing or other users.
+// Must be used after Authenticate middleware.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, isToken := GetScopes(r.Context()); isToken {
+			respondForbidden(w, "personal access tokens cannot be used here")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
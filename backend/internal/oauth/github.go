@@ -0,0 +1,135 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anujgupta/level-up-backend/internal/config"
+)
+
+// GitHubUser is the subset of the GitHub profile we care about. Email is the
+// account's primary address and is only set when GitHub reports it verified.
+type GitHubUser struct {
+	ID    int64
+	Login string
+	Name  string
+	Email string
+}
+
+// GitHub implements the OAuth2 authorization-code flow against GitHub. All
+// endpoints come from config so tests can point it at a fake server.
+type GitHub struct {
+	cfg        *config.Config
+	httpClient *http.Client
+}
+
+func NewGitHub(cfg *config.Config) *GitHub {
+	return &GitHub{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AuthCodeURL returns the URL to send the browser to for consent.
+func (g *GitHub) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("client_id", g.cfg.GitHubClientID)
+	q.Set("redirect_uri", g.cfg.GitHubRedirectURL)
+	q.Set("scope", "read:user user:email")
+	q.Set("state", state)
+	q.Set("allow_signup", "true")
+	return g.cfg.GitHubAuthURL + "?" + q.Encode()
+}
+
+// Exchange trades an authorization code for an access token.
+func (g *GitHub) Exchange(ctx context.Context, code string) (string, error) {
+	form := url.Values{}
+	form.Set("client_id", g.cfg.GitHubClientID)
+	form.Set("client_secret", g.cfg.GitHubClientSecret)
+	form.Set("code", code)
+	form.Set("redirect_uri", g.cfg.GitHubRedirectURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.cfg.GitHubTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := g.do(req, &body); err != nil {
+		return "", fmt.Errorf("exchange code: %w", err)
+	}
+	if body.Error != "" {
+		return "", fmt.Errorf("exchange code: %s: %s", body.Error, body.ErrorDescription)
+	}
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("exchange code: empty access token")
+	}
+	return body.AccessToken, nil
+}
+
+// FetchUser loads the profile and verified primary email for an access token.
+func (g *GitHub) FetchUser(ctx context.Context, accessToken string) (*GitHubUser, error) {
+	var profile struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := g.get(ctx, accessToken, "/user", &profile); err != nil {
+		return nil, fmt.Errorf("fetch github user: %w", err)
+	}
+	if profile.ID == 0 || profile.Login == "" {
+		return nil, fmt.Errorf("fetch github user: incomplete profile")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := g.get(ctx, accessToken, "/user/emails", &emails); err != nil {
+		return nil, fmt.Errorf("fetch github emails: %w", err)
+	}
+
+	user := &GitHubUser{ID: profile.ID, Login: profile.Login, Name: profile.Name}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			user.Email = strings.ToLower(strings.TrimSpace(e.Email))
+			break
+		}
+	}
+	return user, nil
+}
+
+func (g *GitHub) get(ctx context.Context, accessToken, path string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.cfg.GitHubAPIURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	return g.do(req, dst)
+}
+
+func (g *GitHub) do(req *http.Request, dst any) error {
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, req.URL.Path)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
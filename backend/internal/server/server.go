@@ -9,13 +9,16 @@ import (
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog/v2"
 	"github.com/go-chi/httprate"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	dbgen "github.com/anujgupta/level-up-backend/generated/db"
 	"github.com/anujgupta/level-up-backend/internal/auth"
 	"github.com/anujgupta/level-up-backend/internal/config"
+	"github.com/anujgupta/level-up-backend/internal/gamification"
 	"github.com/anujgupta/level-up-backend/internal/handlers"
 	"github.com/anujgupta/level-up-backend/internal/mailer"
 	appmiddleware "github.com/anujgupta/level-up-backend/internal/middleware"
+	"github.com/anujgupta/level-up-backend/internal/oauth"
 )
 
 type Server struct {
@@ -25,6 +28,7 @@ type Server struct {
 
 func New(
 	cfg *config.Config,
+	pool *pgxpool.Pool,
 	queries *dbgen.Queries,
 	authSvc *auth.Service,
 	mailerSvc *mailer.Mailer,
@@ -48,63 +52,193 @@ func New(
 	r.Use(chimiddleware.Recoverer)
 
 	// ── Handlers ─────────────────────────────────────────────────────────────
-	authHandler := handlers.NewAuthHandler(queries, authSvc, mailerSvc)
+	game := gamification.NewService(queries)
+	authHandler := handlers.NewAuthHandler(pool, queries, cfg, authSvc, mailerSvc)
 	paymentsHandler := handlers.NewPaymentsHandler(queries, cfg, mailerSvc, logger)
-	modulesHandler := handlers.NewModulesHandler(queries)
-	lessonsHandler := handlers.NewLessonsHandler(queries)
-	progressHandler := handlers.NewProgressHandler(queries)
-	skillsHandler := handlers.NewSkillsHandler(queries)
+	modulesHandler := handlers.NewModulesHandler(pool, queries)
+	lessonsHandler := handlers.NewLessonsHandler(queries, game)
+	progressHandler := handlers.NewProgressHandler(queries, game)
+	skillsHandler := handlers.NewSkillsHandler(queries, game)
 	submissionsHandler := handlers.NewSubmissionsHandler(queries)
-	adminHandler := handlers.NewAdminHandler(queries)
+	adminHandler := handlers.NewAdminHandler(queries, game)
+	jwksHandler := handlers.NewJWKSHandler(authSvc)
+	oauthHandler := handlers.NewOAuthHandler(queries, authSvc, oauth.NewGitHub(cfg), mailerSvc, logger)
+	tokensHandler := handlers.NewTokensHandler(queries)
+	impersonationHandler := handlers.NewImpersonationHandler(queries, cfg, authSvc)
+	curriculumHandler := handlers.NewCurriculumHandler(pool, queries)
+	searchHandler := handlers.NewSearchHandler(queries)
+	activityHandler := handlers.NewActivityHandler(queries)
+	certificatesHandler := handlers.NewCertificatesHandler(queries, cfg)
 
 	// ── Routes ───────────────────────────────────────────────────────────────
 
 	r.Get("/health", handlers.Health)
+	r.Get("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
+	// Certificate verification is public so employers can check them
+	r.With(httprate.LimitByIP(60, 60)).Get("/certificates/{id}", certificatesHandler.VerifyCertificate)
+	r.With(httprate.LimitByIP(30, 60)).Get("/certificates/{id}/pdf", certificatesHandler.CertificatePDF)
 
 	// Auth (rate limited)
 	r.With(httprate.LimitByIP(10, 60)).Post("/auth/register", authHandler.Register)
 	r.With(httprate.LimitByIP(10, 60)).Post("/auth/login", authHandler.Login)
+	r.With(httprate.LimitByIP(10, 60)).Post("/auth/login/2fa", authHandler.CompleteMFALogin)
 	r.With(httprate.LimitByIP(20, 60)).Post("/auth/refresh", authHandler.RefreshToken)
+	r.With(httprate.LimitByIP(20, 60)).Post("/auth/logout", authHandler.Logout)
+	r.With(httprate.LimitByIP(5, 60)).Post("/auth/password/forgot", authHandler.ForgotPassword)
+	r.With(httprate.LimitByIP(10, 60)).Post("/auth/password/reset", authHandler.ResetPassword)
+	r.With(httprate.LimitByIP(10, 60)).Post("/auth/verify-email", authHandler.VerifyEmail)
+	r.With(httprate.LimitByIP(10, 60)).Post("/auth/email/approve", authHandler.ApproveEmailChange)
+	r.With(httprate.LimitByIP(10, 60)).Post("/auth/email/confirm", authHandler.ConfirmEmailChange)
+
+	// GitHub sign-in (only when configured)
+	if cfg.GitHubEnabled() {
+		r.With(httprate.LimitByIP(20, 60)).Get("/auth/github", oauthHandler.GitHubLogin)
+		r.With(httprate.LimitByIP(10, 60)).Post("/auth/github/callback", oauthHandler.GitHubCallback)
+	}
 
 	// Stripe webhook — raw body must be captured before any body parsing
 	r.With(appmiddleware.StripeRawBody).Post("/payments/webhook", paymentsHandler.StripeWebhook)
 
-	// JWT-protected routes
-	authenticate := appmiddleware.Authenticate(authSvc)
+	// JWT- or token-protected routes
+	authenticate := appmiddleware.Authenticate(authSvc, queries)
 	requireActive := appmiddleware.RequireActive(queries)
+	requireScope := appmiddleware.RequireScope
+	blockImpersonation := appmiddleware.BlockImpersonation
 
 	r.Group(func(r chi.Router) {
 		r.Use(authenticate)
 
-		// Payments
-		r.Post("/payments/checkout", paymentsHandler.CreateCheckoutSession)
-		r.Get("/payments/subscription", paymentsHandler.GetSubscription)
+		// Session-only routes (personal access and impersonation tokens are rejected)
+		r.Group(func(r chi.Router) {
+			r.Use(appmiddleware.RequireSession)
+			r.Use(appmiddleware.BlockImpersonation)
 
-		// Progress + submissions (JWT only, no sub gate)
-		r.Get("/progress", progressHandler.GetProgress)
-		r.Get("/submissions", submissionsHandler.ListSubmissions)
-		r.Get("/submissions/{id}", submissionsHandler.GetSubmission)
+			r.Post("/auth/logout-all", authHandler.LogoutAll)
+			r.With(httprate.LimitByIP(5, 60)).Post("/auth/verify-email/resend", authHandler.ResendVerificationEmail)
+			if cfg.GitHubEnabled() {
+				r.Get("/auth/github/link", oauthHandler.GitHubLink)
+			}
 
-		// Subscription-gated content
+			// Two-factor authentication
+			r.Group(func(r chi.Router) {
+				r.Use(httprate.LimitByIP(10, 60))
+
+				r.Post("/auth/2fa/setup", authHandler.SetupMFA)
+				r.Post("/auth/2fa/enable", authHandler.EnableMFA)
+				r.Post("/auth/2fa/disable", authHandler.DisableMFA)
+				r.Post("/auth/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
+			})
+
+			// Account
+			r.Patch("/me", authHandler.UpdateMe)
+			r.With(httprate.LimitByIP(5, 60)).Delete("/me", authHandler.DeleteMe)
+			r.With(httprate.LimitByIP(5, 60)).Get("/me/export", authHandler.ExportMe)
+			r.With(httprate.LimitByIP(10, 60)).Post("/me/password", authHandler.ChangePassword)
+			r.With(httprate.LimitByIP(5, 60)).Post("/me/email", authHandler.RequestEmailChange)
+
+			// Personal access tokens
+			r.Get("/me/tokens", tokensHandler.ListTokens)
+			r.Post("/me/tokens", tokensHandler.CreateToken)
+			r.Delete("/me/tokens/{id}", tokensHandler.RevokeToken)
+
+			// Payments
+			r.Post("/payments/checkout", paymentsHandler.CreateCheckoutSession)
+			r.Get("/payments/subscription", paymentsHandler.GetSubscription)
+
+			// Staff routes, gated per permission
+			r.Group(func(r chi.Router) {
+				if cfg.RequireAdminMFA {
+					r.Use(appmiddleware.RequireMFA)
+				}
+				requirePermission := func(perm string) func(http.Handler) http.Handler {
+					return appmiddleware.RequirePermission(queries, perm)
+				}
+
+				r.With(requirePermission(auth.PermSubmissionsRead)).Get("/admin/submissions", adminHandler.ListSubmissions)
+				r.With(requirePermission(auth.PermSubmissionsReview)).Put("/admin/submissions/{id}/review", adminHandler.ReviewSubmission)
+
+				r.With(requirePermission(auth.PermUsersManage)).Get("/admin/roles", adminHandler.ListRoles)
+				r.With(requirePermission(auth.PermUsersManage)).Put("/admin/users/{id}/role", adminHandler.SetUserRole)
+				r.With(requirePermission(auth.PermUsersManage)).Post("/admin/users/{id}/unlock", adminHandler.UnlockUser)
+
+				r.With(requirePermission(auth.PermUsersImpersonate)).Post("/admin/impersonate", impersonationHandler.Impersonate)
+				r.With(requirePermission(auth.PermUsersImpersonate)).Get("/admin/impersonations", impersonationHandler.ListImpersonations)
+				r.With(requirePermission(auth.PermUsersImpersonate)).Post("/admin/impersonations/{id}/end", impersonationHandler.EndImpersonation)
+
+				// Curriculum
+				r.Group(func(r chi.Router) {
+					r.Use(requirePermission(auth.PermContentEdit))
+
+					r.Post("/admin/modules", curriculumHandler.CreateModule)
+					r.Put("/admin/modules/order", curriculumHandler.ReorderModules)
+					r.Patch("/admin/modules/{id}", curriculumHandler.UpdateModule)
+					r.Delete("/admin/modules/{id}", curriculumHandler.DeleteModule)
+					r.Put("/admin/modules/{id}/status", curriculumHandler.SetModuleStatus)
+					r.Get("/admin/modules/{id}/prerequisites", curriculumHandler.GetModulePrerequisites)
+					r.Put("/admin/modules/{id}/prerequisites", curriculumHandler.SetModulePrerequisites)
+
+					r.Post("/admin/modules/{id}/lessons", curriculumHandler.CreateLesson)
+					r.Put("/admin/modules/{id}/lessons/order", curriculumHandler.ReorderLessons)
+					r.Patch("/admin/lessons/{id}", curriculumHandler.UpdateLesson)
+					r.Delete("/admin/lessons/{id}", curriculumHandler.DeleteLesson)
+					r.Put("/admin/lessons/{id}/status", curriculumHandler.SetLessonStatus)
+					r.Get("/admin/lessons/{id}/prerequisites", curriculumHandler.GetLessonPrerequisites)
+					r.Put("/admin/lessons/{id}/prerequisites", curriculumHandler.SetLessonPrerequisites)
+					r.Get("/admin/lessons/{id}/revisions", curriculumHandler.ListLessonRevisions)
+					r.Get("/admin/lessons/{id}/revisions/{revision}", curriculumHandler.GetLessonRevision)
+					r.Get("/admin/lessons/{id}/revisions/{revision}/diff", curriculumHandler.DiffLessonRevision)
+					r.Post("/admin/lessons/{id}/revisions/{revision}/restore", curriculumHandler.RestoreLessonRevision)
+
+					r.Post("/admin/modules/{id}/skills", curriculumHandler.CreateSkill)
+					r.Put("/admin/modules/{id}/skills/order", curriculumHandler.ReorderSkills)
+					r.Patch("/admin/skills/{id}", curriculumHandler.UpdateSkill)
+					r.Delete("/admin/skills/{id}", curriculumHandler.DeleteSkill)
+
+					r.Put("/admin/modules/{id}/assignment", curriculumHandler.PutAssignment)
+					r.Delete("/admin/modules/{id}/assignment", curriculumHandler.DeleteAssignment)
+				})
+			})
+		})
+
+		// Browsing, progress + submissions (no sub gate; lesson bodies are
+		// gated in the handler so free users can read preview lessons)
 		r.Group(func(r chi.Router) {
-			r.Use(requireActive)
+			r.Use(requireScope(auth.ScopeRead))
 
+			r.Get("/me", authHandler.GetMe)
 			r.Get("/modules", modulesHandler.ListModules)
 			r.Get("/modules/{slug}", modulesHandler.GetModule)
 			r.Get("/modules/{slug}/lessons/{lessonSlug}", lessonsHandler.GetLesson)
-			r.Post("/lessons/{id}/complete", lessonsHandler.CompleteLesson)
-			r.Get("/modules/{slug}/skills", skillsHandler.GetModuleSkills)
-			r.Post("/skills/{id}/complete", skillsHandler.CompleteSkill)
-			r.Get("/modules/{slug}/assignment", submissionsHandler.GetAssignment)
-			r.Post("/submissions", submissionsHandler.CreateSubmission)
+			r.Get("/progress", progressHandler.GetProgress)
+			r.Get("/me/resume", progressHandler.GetResume)
+			r.Get("/me/activity", activityHandler.GetActivity)
+			r.Get("/me/certificates", certificatesHandler.ListMyCertificates)
+			r.Get("/submissions", submissionsHandler.ListSubmissions)
+			r.Get("/submissions/{id}", submissionsHandler.GetSubmission)
+
+			// Search is open to everyone; lesson excerpts are gated inside
+			r.With(httprate.LimitByIP(60, 60)).Get("/search", searchHandler.Search)
 		})
 
-		// Admin routes
+		// Reading position and starting over (no sub gate, preview lessons are free)
+		r.With(blockImpersonation, requireScope(auth.ScopeProgress)).Post("/lessons/{id}/view", lessonsHandler.RecordView)
+		r.With(blockImpersonation, requireScope(auth.ScopeProgress)).Post("/modules/{slug}/reset", modulesHandler.ResetModule)
+
+		// Certificates stay available after a subscription lapses
+		r.With(blockImpersonation, requireScope(auth.ScopeProgress)).Post("/modules/{slug}/certificate", certificatesHandler.IssueCertificate)
+
+		// Subscription-gated content
 		r.Group(func(r chi.Router) {
-			r.Use(appmiddleware.RequireAdmin)
+			r.Use(requireActive)
 
-			r.Get("/admin/submissions", adminHandler.ListSubmissions)
-			r.Put("/admin/submissions/{id}/review", adminHandler.ReviewSubmission)
+			r.With(blockImpersonation, requireScope(auth.ScopeProgress)).Post("/lessons/{id}/complete", lessonsHandler.CompleteLesson)
+			r.With(blockImpersonation, requireScope(auth.ScopeProgress)).Delete("/lessons/{id}/complete", lessonsHandler.UncompleteLesson)
+			r.With(requireScope(auth.ScopeRead)).Get("/modules/{slug}/skills", skillsHandler.GetModuleSkills)
+			r.With(blockImpersonation, requireScope(auth.ScopeProgress)).Post("/skills/{id}/complete", skillsHandler.CompleteSkill)
+			r.With(blockImpersonation, requireScope(auth.ScopeProgress)).Delete("/skills/{id}/complete", skillsHandler.UncompleteSkill)
+			r.With(requireScope(auth.ScopeRead)).Get("/modules/{slug}/assignment", submissionsHandler.GetAssignment)
+			r.With(blockImpersonation, requireScope(auth.ScopeSubmissions)).Post("/submissions", submissionsHandler.CreateSubmission)
 		})
 	})
 